@@ -0,0 +1,63 @@
+package render_test
+
+import (
+	"math"
+	"testing"
+
+	"git.kirsle.net/go/render"
+)
+
+func TestWuLinePoints(t *testing.T) {
+	// A line between whole numbers covers the same pixels as LinePoints.
+	var covered = map[render.Point]float64{}
+	render.WuLinePoints(2, 3, 12, 3)(func(pt render.Point, coverage float64) bool {
+		covered[pt] += coverage
+		return true
+	})
+	render.LinePoints(render.NewPoint(2, 3), render.NewPoint(12, 3))(func(pt render.Point) bool {
+		if covered[pt] == 0 {
+			t.Errorf("horizontal line: expected %s to be covered", pt)
+		}
+		delete(covered, pt)
+		return true
+	})
+	if len(covered) > 0 {
+		t.Errorf("horizontal line: unexpected pixels covered: %v", covered)
+	}
+
+	// A line between pixel rows shares its coverage between them, and each
+	// column adds up to a whole pixel.
+	var columns = map[int]float64{}
+	render.WuLinePoints(0, 0, 20, 5.5)(func(pt render.Point, coverage float64) bool {
+		if coverage <= 0 || coverage > 1 {
+			t.Errorf("coverage at %s out of range: %f", pt, coverage)
+		}
+		columns[pt.X] += coverage
+		return true
+	})
+	for x := 1; x < 20; x++ {
+		if math.Abs(columns[x]-1) > 1e-9 {
+			t.Errorf("column %d: expected a total coverage of 1, got %f", x, columns[x])
+		}
+	}
+
+	// Steep lines step along Y instead.
+	var rows = map[int]float64{}
+	render.WuLinePoints(0.5, 0, 3.5, 20)(func(pt render.Point, coverage float64) bool {
+		rows[pt.Y] += coverage
+		return true
+	})
+	if len(rows) != 21 {
+		t.Errorf("steep line: expected 21 rows, got %d", len(rows))
+	}
+
+	// Stops early.
+	var count int
+	render.WuLinePoints(0, 0, 100, 37)(func(pt render.Point, coverage float64) bool {
+		count++
+		return count < 5
+	})
+	if count != 5 {
+		t.Errorf("stop early: expected 5 pixels, got %d", count)
+	}
+}
@@ -0,0 +1,169 @@
+package render
+
+import "math"
+
+// bezierTolerance is how far, in pixels, the straight lines of a flattened
+// curve may stray from the true curve.
+const bezierTolerance = 0.25
+
+// bezierMaxDepth limits how many times a curve is split in half.
+const bezierMaxDepth = 16
+
+// IterQuadBezier is a generator that returns the X,Y coordinates to draw a
+// quadratic Bezier curve from A to C, bending towards the control point B.
+//
+// Prefer QuadBezierPoints, which doesn't need a goroutine.
+func IterQuadBezier(A, B, C Point) chan Point {
+	return pointChan(QuadBezierPoints(A, B, C))
+}
+
+// IterCubicBezier is a generator that returns the X,Y coordinates to draw a
+// cubic Bezier curve from A to D, with control points B and C.
+//
+// Prefer CubicBezierPoints, which doesn't need a goroutine.
+func IterCubicBezier(A, B, C, D Point) chan Point {
+	return pointChan(CubicBezierPoints(A, B, C, D))
+}
+
+// QuadBezierPoints iterates over the X,Y coordinates to draw a quadratic
+// Bezier curve. It yields the same points as IterQuadBezier.
+//
+// The curve is flattened adaptively: it's split into as few straight lines as
+// it takes to stay within a quarter pixel of the true curve.
+func QuadBezierPoints(A, B, C Point) func(yield func(Point) bool) {
+	return func(yield func(Point) bool) {
+		polylinePoints(A, func(fn func([2]float64) bool) {
+			flattenQuad(floatPoint(A), floatPoint(B), floatPoint(C), bezierTolerance, fn)
+		}, yield)
+	}
+}
+
+// CubicBezierPoints iterates over the X,Y coordinates to draw a cubic Bezier
+// curve. It yields the same points as IterCubicBezier.
+//
+// The curve is flattened adaptively, like QuadBezierPoints.
+func CubicBezierPoints(A, B, C, D Point) func(yield func(Point) bool) {
+	return func(yield func(Point) bool) {
+		polylinePoints(A, func(fn func([2]float64) bool) {
+			flattenCubic(floatPoint(A), floatPoint(B), floatPoint(C), floatPoint(D), bezierTolerance, fn)
+		}, yield)
+	}
+}
+
+// polylinePoints yields the pixels of the lines from the start point through
+// each vertex given by the flatten function, without repeating the pixels
+// where the lines meet.
+func polylinePoints(start Point, flatten func(fn func([2]float64) bool), yield func(Point) bool) {
+	if !yield(start) {
+		return
+	}
+
+	// The last pixel of a line isn't always its end point, as LinePoints
+	// steps in floats, so compare against the last pixel actually drawn.
+	var (
+		prev, last = start, start
+		stopped    bool
+	)
+	flatten(func(v [2]float64) bool {
+		var (
+			next = NewPoint(round(v[0]), round(v[1]))
+			ok   = true
+		)
+		if next == prev {
+			return true
+		}
+
+		LinePoints(prev, next)(func(pt Point) bool {
+			if pt == last {
+				return true
+			}
+			last = pt
+			ok = yield(pt)
+			return ok
+		})
+		prev = next
+		stopped = !ok
+		return ok
+	})
+
+	// Always finish on the end point of the curve.
+	if !stopped && last != prev {
+		yield(prev)
+	}
+}
+
+// flattenQuad splits a quadratic Bezier curve into straight lines, calling fn
+// with the end of each line until it returns false.
+func flattenQuad(p0, p1, p2 [2]float64, tolerance float64, fn func([2]float64) bool) bool {
+	return flattenQuadDepth(p0, p1, p2, tolerance, 0, fn)
+}
+
+func flattenQuadDepth(p0, p1, p2 [2]float64, tolerance float64, depth int, fn func([2]float64) bool) bool {
+	if depth >= bezierMaxDepth || distanceToSegment(p1, p0, p2) <= tolerance {
+		return fn(p2)
+	}
+
+	// Split the curve in half with de Casteljau's algorithm.
+	var (
+		a   = midpoint(p0, p1)
+		b   = midpoint(p1, p2)
+		mid = midpoint(a, b)
+	)
+	return flattenQuadDepth(p0, a, mid, tolerance, depth+1, fn) &&
+		flattenQuadDepth(mid, b, p2, tolerance, depth+1, fn)
+}
+
+// flattenCubic splits a cubic Bezier curve into straight lines, calling fn
+// with the end of each line until it returns false.
+func flattenCubic(p0, p1, p2, p3 [2]float64, tolerance float64, fn func([2]float64) bool) bool {
+	return flattenCubicDepth(p0, p1, p2, p3, tolerance, 0, fn)
+}
+
+func flattenCubicDepth(p0, p1, p2, p3 [2]float64, tolerance float64, depth int, fn func([2]float64) bool) bool {
+	if depth >= bezierMaxDepth ||
+		math.Max(distanceToSegment(p1, p0, p3), distanceToSegment(p2, p0, p3)) <= tolerance {
+		return fn(p3)
+	}
+
+	var (
+		a   = midpoint(p0, p1)
+		b   = midpoint(p1, p2)
+		c   = midpoint(p2, p3)
+		ab  = midpoint(a, b)
+		bc  = midpoint(b, c)
+		mid = midpoint(ab, bc)
+	)
+	return flattenCubicDepth(p0, a, ab, mid, tolerance, depth+1, fn) &&
+		flattenCubicDepth(mid, bc, c, p3, tolerance, depth+1, fn)
+}
+
+// distanceToSegment returns how far a point is from the line segment from a
+// to b.
+//
+// Used as the flatness test of the curves, it must be the segment and not
+// the whole line: a control point in line with the ends but past them still
+// pulls the curve out beyond the ends.
+func distanceToSegment(p, a, b [2]float64) float64 {
+	var (
+		dx     = b[0] - a[0]
+		dy     = b[1] - a[1]
+		length = dx*dx + dy*dy
+	)
+	if length == 0 {
+		return math.Hypot(p[0]-a[0], p[1]-a[1])
+	}
+
+	var t = ((p[0]-a[0])*dx + (p[1]-a[1])*dy) / length
+	t = math.Max(0, math.Min(1, t))
+	return math.Hypot(p[0]-a[0]-t*dx, p[1]-a[1]-t*dy)
+}
+
+// midpoint returns the point halfway between a and b.
+func midpoint(a, b [2]float64) [2]float64 {
+	return [2]float64{(a[0] + b[0]) / 2, (a[1] + b[1]) / 2}
+}
+
+// floatPoint converts a Point to float coordinates.
+func floatPoint(p Point) [2]float64 {
+	return [2]float64{float64(p.X), float64(p.Y)}
+}
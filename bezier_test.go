@@ -0,0 +1,120 @@
+package render_test
+
+import (
+	"testing"
+
+	"git.kirsle.net/go/render"
+)
+
+func TestBezierPoints(t *testing.T) {
+	var (
+		p     = render.NewPoint
+		tests = []struct {
+			Name   string
+			Seq    func(yield func(render.Point) bool)
+			Start  render.Point
+			End    render.Point
+			Bounds render.Rect
+		}{
+			{
+				Name:   "quadratic",
+				Seq:    render.QuadBezierPoints(p(0, 0), p(50, 100), p(100, 0)),
+				Start:  p(0, 0),
+				End:    p(100, 0),
+				Bounds: render.Rect{X: 0, Y: 0, W: 101, H: 51},
+			},
+			{
+				Name:   "cubic S curve",
+				Seq:    render.CubicBezierPoints(p(0, 0), p(100, 0), p(0, 100), p(100, 100)),
+				Start:  p(0, 0),
+				End:    p(100, 100),
+				Bounds: render.Rect{X: 0, Y: 0, W: 101, H: 101},
+			},
+			{
+				Name:   "cubic loop",
+				Seq:    render.CubicBezierPoints(p(10, 10), p(60, -20), p(-40, -20), p(10, 10)),
+				Start:  p(10, 10),
+				End:    p(10, 10),
+				Bounds: render.Rect{X: -4, Y: -12, W: 29, H: 23},
+			},
+		}
+	)
+
+	for _, test := range tests {
+		points := collect(test.Seq)
+		if points[0] != test.Start || points[len(points)-1] != test.End {
+			t.Errorf("%s: expected to go from %s to %s, got %s to %s",
+				test.Name, test.Start, test.End, points[0], points[len(points)-1],
+			)
+		}
+
+		// Each pixel steps to a neighbor of the last, without gaps or
+		// repeats, inside the bounds of the curve.
+		var bounds render.Rect
+		for i, pt := range points {
+			bounds = bounds.Union(render.Rect{X: pt.X, Y: pt.Y, W: 1, H: 1})
+			if i == 0 {
+				continue
+			}
+			dx, dy := render.AbsInt(pt.X-points[i-1].X), render.AbsInt(pt.Y-points[i-1].Y)
+			if dx > 1 || dy > 1 || (dx == 0 && dy == 0) {
+				t.Errorf("%s: step from %s to %s", test.Name, points[i-1], pt)
+			}
+		}
+		if !test.Bounds.Contains(bounds) {
+			t.Errorf("%s: expected the curve inside %s, got %s", test.Name, test.Bounds, bounds)
+		}
+	}
+}
+
+func TestBezierCollinear(t *testing.T) {
+	var p = render.NewPoint
+
+	// Control points in line with the ends, but past them, pull the curve
+	// out beyond the ends and back.
+	var tests = []struct {
+		Name string
+		Seq  func(yield func(render.Point) bool)
+		End  render.Point
+		MinX int
+		MaxX int
+	}{
+		{
+			Name: "quadratic",
+			Seq:  render.QuadBezierPoints(p(0, 0), p(100, 0), p(10, 0)),
+			End:  p(10, 0),
+			MinX: 0,
+			MaxX: 53, // the curve turns around at x=52.6
+		},
+		{
+			Name: "cubic",
+			Seq:  render.CubicBezierPoints(p(0, 0), p(-60, 0), p(100, 0), p(40, 0)),
+			End:  p(40, 0),
+			MinX: -14, // the curve turns around at x=-13.7 and x=53.7
+			MaxX: 54,
+		},
+	}
+	for _, test := range tests {
+		var (
+			points     = collect(test.Seq)
+			minX, maxX = points[0].X, points[0].X
+		)
+		for _, pt := range points {
+			if pt.X < minX {
+				minX = pt.X
+			}
+			if pt.X > maxX {
+				maxX = pt.X
+			}
+			if pt.Y != 0 {
+				t.Errorf("%s: expected the curve along y=0, got %s", test.Name, pt)
+			}
+		}
+		if points[len(points)-1] != test.End {
+			t.Errorf("%s: expected to end at %s, got %s", test.Name, test.End, points[len(points)-1])
+		}
+		if minX != test.MinX || maxX != test.MaxX {
+			t.Errorf("%s: expected x from %d to %d, got %d to %d", test.Name, test.MinX, test.MaxX, minX, maxX)
+		}
+	}
+}
@@ -0,0 +1,30 @@
+package render
+
+// BlendMode is how the Engine mixes the colors it draws with the pixels
+// already on screen. The modes match the ones of SDL2; for blending colors
+// and images in Go, see CompositeOp.
+type BlendMode int
+
+// BlendMode values.
+const (
+	// BlendAlpha draws colors over the pixels with normal alpha blending.
+	// It's the default.
+	BlendAlpha BlendMode = iota
+
+	// BlendNone replaces the pixels with the color, alpha and all, such as to
+	// punch transparent holes in a texture.
+	BlendNone
+
+	// BlendAdditive adds the color, weighted by its alpha, to the pixels,
+	// which brightens them for glows, lights and particles.
+	BlendAdditive
+
+	// BlendModulate multiplies the pixels by the color, darkening them, such
+	// as to tint them or light a scene with a light map.
+	BlendModulate
+
+	// BlendMultiply multiplies the pixels by the color like BlendModulate,
+	// but blended by the color's alpha, so translucent colors darken less:
+	// pixel × (color × alpha + 1 − alpha).
+	BlendMultiply
+)
@@ -0,0 +1,306 @@
+package render
+
+import (
+	"fmt"
+	"math"
+)
+
+// Camera maps a scene in world coordinates onto an area of the screen, for
+// drawing levels that are larger than the window.
+//
+// The Position is the world coordinate shown at the top-left corner of the
+// Viewport, and Zoom scales world pixels to screen pixels: at a Zoom of 2,
+// each world pixel is drawn as a 2x2 box. A zero Zoom is treated as 1.
+type Camera struct {
+	Position Point
+	Zoom     float64
+	Viewport Rect
+}
+
+// NewCamera creates a camera looking at the world origin at 100% zoom.
+func NewCamera(viewport Rect) *Camera {
+	return &Camera{
+		Zoom:     1,
+		Viewport: viewport,
+	}
+}
+
+func (c Camera) String() string {
+	return fmt.Sprintf("Camera<%s zoom %g in %s>", c.Position, c.zoom(), c.Viewport)
+}
+
+// zoom returns the camera's zoom, treating the zero value as 100%.
+func (c Camera) zoom() float64 {
+	if c.Zoom <= 0 {
+		return 1
+	}
+	return c.Zoom
+}
+
+// Matrix returns the transform from world coordinates to the screen.
+func (c Camera) Matrix() Matrix {
+	var zoom = c.zoom()
+	return Translation(float64(c.Viewport.X), float64(c.Viewport.Y)).
+		Scale(zoom, zoom).
+		Translate(-float64(c.Position.X), -float64(c.Position.Y))
+}
+
+// WorldToScreen converts a world coordinate to where it is on the screen.
+func (c Camera) WorldToScreen(p Point) Point {
+	return c.Matrix().TransformPoint(p)
+}
+
+// ScreenToWorld converts a screen coordinate, such as the mouse cursor, to
+// the world pixel that is drawn there.
+func (c Camera) ScreenToWorld(p Point) Point {
+	var zoom = c.zoom()
+	return Point{
+		X: c.Position.X + int(math.Floor(float64(p.X-c.Viewport.X)/zoom)),
+		Y: c.Position.Y + int(math.Floor(float64(p.Y-c.Viewport.Y)/zoom)),
+	}
+}
+
+// View returns the area of the world that is visible in the viewport.
+func (c Camera) View() Rect {
+	var zoom = c.zoom()
+	return Rect{
+		X: c.Position.X,
+		Y: c.Position.Y,
+		W: int(math.Ceil(float64(c.Viewport.W) / zoom)),
+		H: int(math.Ceil(float64(c.Viewport.H) / zoom)),
+	}
+}
+
+// Visible returns whether any part of a world rect is in view. Rects that
+// are not visible can be skipped when drawing.
+func (c Camera) Visible(r Rect) bool {
+	return !c.View().Intersection(r.Normalize()).IsEmpty()
+}
+
+// CenterOn scrolls the camera so the world point is in the middle of the
+// viewport.
+func (c *Camera) CenterOn(p Point) {
+	var zoom = c.zoom()
+	c.Position = Point{
+		X: p.X - int(float64(c.Viewport.W)/zoom/2),
+		Y: p.Y - int(float64(c.Viewport.H)/zoom/2),
+	}
+}
+
+// Scroll moves the camera by a distance in screen pixels, for example the
+// distance the mouse was dragged. The world moves by less than a screen
+// pixel per pixel when zoomed in.
+func (c *Camera) Scroll(delta Point) {
+	var zoom = c.zoom()
+	c.Position.X += int(math.Round(float64(delta.X) / zoom))
+	c.Position.Y += int(math.Round(float64(delta.Y) / zoom))
+}
+
+// ZoomAt changes the zoom while keeping the world pixel under a screen
+// point in place, like zooming towards the mouse cursor.
+func (c *Camera) ZoomAt(zoom float64, screen Point) {
+	if zoom <= 0 {
+		return
+	}
+
+	var anchor = c.ScreenToWorld(screen)
+	c.Zoom = zoom
+	c.Position = Point{
+		X: anchor.X - int(math.Floor(float64(screen.X-c.Viewport.X)/zoom)),
+		Y: anchor.Y - int(math.Floor(float64(screen.Y-c.Viewport.Y)/zoom)),
+	}
+}
+
+// Draw a scene through the camera.
+//
+// Drawing is clipped to the Viewport and the draw function works in world
+// coordinates. The Engine given to it skips shapes and textures that are
+// out of view, so a large scene can simply draw everything it has.
+func (c Camera) Draw(e Engine, draw func(Engine)) {
+	e.PushClip(c.Viewport)
+	e.PushTransform(c.Matrix())
+	draw(&cameraEngine{
+		Engine: e,
+		camera: c,
+	})
+	e.PopTransform()
+	e.PopClip()
+}
+
+// cameraEngine wraps an Engine to cull drawing calls that are outside the
+// camera's view.
+type cameraEngine struct {
+	Engine
+	camera    Camera
+	transform TransformStack // transforms pushed by the draw function
+}
+
+// PushTransform applies a transform to the drawing calls, keeping track of
+// it to map them into the world for culling.
+func (e *cameraEngine) PushTransform(m Matrix) {
+	e.transform.Push(m)
+	e.Engine.PushTransform(m)
+}
+
+// PopTransform removes the last transform pushed.
+func (e *cameraEngine) PopTransform() {
+	e.transform.Pop()
+	e.Engine.PopTransform()
+}
+
+// visible returns whether a rect in the coordinates of the current
+// transform is in view.
+func (e *cameraEngine) visible(r Rect) bool {
+	var world = e.transform.Current().TransformRect(r.Normalize())
+
+	// Shrunk down, a shape still draws at least a pixel.
+	world.W = maxInt(world.W, 1)
+	world.H = maxInt(world.H, 1)
+	return e.camera.Visible(world)
+}
+
+// DrawPoint draws a pixel if it's in view.
+func (e *cameraEngine) DrawPoint(color Color, point Point) {
+	if e.visible(Rect{X: point.X, Y: point.Y, W: 1, H: 1}) {
+		e.Engine.DrawPoint(color, point)
+	}
+}
+
+// DrawLine draws a line if its bounding box is in view.
+func (e *cameraEngine) DrawLine(color Color, a, b Point) {
+	var bounds = Rect{
+		X: minInt(a.X, b.X),
+		Y: minInt(a.Y, b.Y),
+		W: maxInt(a.X, b.X) - minInt(a.X, b.X) + 1,
+		H: maxInt(a.Y, b.Y) - minInt(a.Y, b.Y) + 1,
+	}
+	if e.visible(bounds) {
+		e.Engine.DrawLine(color, a, b)
+	}
+}
+
+// DrawLineAA draws an anti-aliased line if its bounding box is in view.
+func (e *cameraEngine) DrawLineAA(color Color, x1, y1, x2, y2 float64) {
+	var (
+		left   = int(math.Floor(math.Min(x1, x2)))
+		top    = int(math.Floor(math.Min(y1, y2)))
+		right  = int(math.Ceil(math.Max(x1, x2)))
+		bottom = int(math.Ceil(math.Max(y1, y2)))
+	)
+	if e.visible(Rect{X: left, Y: top, W: right - left + 1, H: bottom - top + 1}) {
+		e.Engine.DrawLineAA(color, x1, y1, x2, y2)
+	}
+}
+
+// DrawPolyline draws connected lines if any of them are in view.
+func (e *cameraEngine) DrawPolyline(color Color, style StrokeStyle, points []Point) {
+	// Pad the points by how far the stroke can reach past them.
+	var pad = style.width()
+	if style.Join == JoinMiter {
+		var limit = style.MiterLimit
+		if limit <= 0 {
+			limit = DefaultMiterLimit
+		}
+		pad = int(math.Ceil(limit * float64(pad) / 2))
+	}
+	if e.visible(pointBounds(points, pad)) {
+		e.Engine.DrawPolyline(color, style, points)
+	}
+}
+
+// DrawPolygon draws the outline of a polygon if it's in view.
+func (e *cameraEngine) DrawPolygon(color Color, points []Point) {
+	if e.visible(pointBounds(points, 1)) {
+		e.Engine.DrawPolygon(color, points)
+	}
+}
+
+// FillPolygon draws a filled polygon if it's in view.
+func (e *cameraEngine) FillPolygon(color Color, points []Point, rule FillRule) {
+	if e.visible(pointBounds(points, 0)) {
+		e.Engine.FillPolygon(color, points, rule)
+	}
+}
+
+// DrawEllipse draws the outline of an ellipse if it's in view.
+func (e *cameraEngine) DrawEllipse(color Color, rect Rect) {
+	if e.visible(rect) {
+		e.Engine.DrawEllipse(color, rect)
+	}
+}
+
+// FillEllipse draws a filled ellipse if it's in view.
+func (e *cameraEngine) FillEllipse(color Color, rect Rect) {
+	if e.visible(rect) {
+		e.Engine.FillEllipse(color, rect)
+	}
+}
+
+// DrawCircle draws the outline of a circle if it's in view.
+func (e *cameraEngine) DrawCircle(color Color, center Point, radius int) {
+	if e.visible(pointBounds([]Point{center}, radius)) {
+		e.Engine.DrawCircle(color, center, radius)
+	}
+}
+
+// DrawArc draws part of the outline of an ellipse if it's in view.
+func (e *cameraEngine) DrawArc(color Color, rect Rect, start, end float64) {
+	if e.visible(rect) {
+		e.Engine.DrawArc(color, rect, start, end)
+	}
+}
+
+// DrawQuadBezier draws a quadratic Bezier curve if it's in view. The curve
+// stays inside the bounds of its points.
+func (e *cameraEngine) DrawQuadBezier(color Color, a, b, c Point) {
+	if e.visible(pointBounds([]Point{a, b, c}, 0)) {
+		e.Engine.DrawQuadBezier(color, a, b, c)
+	}
+}
+
+// DrawCubicBezier draws a cubic Bezier curve if it's in view.
+func (e *cameraEngine) DrawCubicBezier(color Color, a, b, c, d Point) {
+	if e.visible(pointBounds([]Point{a, b, c, d}, 0)) {
+		e.Engine.DrawCubicBezier(color, a, b, c, d)
+	}
+}
+
+// DrawRect draws a rectangle outline if it's in view.
+func (e *cameraEngine) DrawRect(color Color, rect Rect) {
+	if e.visible(rect) {
+		e.Engine.DrawRect(color, rect)
+	}
+}
+
+// DrawBox draws a filled rectangle if it's in view.
+func (e *cameraEngine) DrawBox(color Color, rect Rect) {
+	if e.visible(rect) {
+		e.Engine.DrawBox(color, rect)
+	}
+}
+
+// Copy a texture if its destination is in view.
+func (e *cameraEngine) Copy(t Texturer, src, dst Rect) {
+	if e.visible(dst) {
+		e.Engine.Copy(t, src, dst)
+	}
+}
+
+// pointBounds returns the rect around a list of points, padded by a number
+// of pixels on each side.
+func pointBounds(points []Point, pad int) Rect {
+	if len(points) == 0 {
+		return Rect{}
+	}
+
+	var bounds = Rect{X: points[0].X, Y: points[0].Y, W: 1, H: 1}
+	for _, pt := range points[1:] {
+		bounds = bounds.Union(Rect{X: pt.X, Y: pt.Y, W: 1, H: 1})
+	}
+	return Rect{
+		X: bounds.X - pad,
+		Y: bounds.Y - pad,
+		W: bounds.W + pad*2,
+		H: bounds.H + pad*2,
+	}
+}
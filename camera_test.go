@@ -0,0 +1,128 @@
+package render_test
+
+import (
+	"testing"
+
+	"git.kirsle.net/go/render"
+	"git.kirsle.net/go/render/record"
+	"git.kirsle.net/go/render/software"
+)
+
+func TestCamera(t *testing.T) {
+	camera := render.NewCamera(render.Rect{X: 10, Y: 20, W: 100, H: 50})
+	camera.Position = render.NewPoint(500, 300)
+	camera.Zoom = 2
+
+	// World to screen and back.
+	if actual := camera.WorldToScreen(render.NewPoint(510, 305)); actual != render.NewPoint(30, 30) {
+		t.Errorf("WorldToScreen: expected 30,30, got %s", actual)
+	}
+	for _, screen := range []render.Point{{X: 30, Y: 30}, {X: 31, Y: 31}} {
+		if actual := camera.ScreenToWorld(screen); actual != render.NewPoint(510, 305) {
+			t.Errorf("ScreenToWorld(%s): expected 510,305, got %s", screen, actual)
+		}
+	}
+
+	// Culling.
+	if expect := (render.Rect{X: 500, Y: 300, W: 50, H: 25}); camera.View() != expect {
+		t.Errorf("View: expected %s, got %s", expect, camera.View())
+	}
+	var tests = []struct {
+		Rect    render.Rect
+		Visible bool
+	}{
+		{render.Rect{X: 520, Y: 310, W: 5, H: 5}, true},
+		{render.Rect{X: 490, Y: 290, W: 11, H: 11}, true},
+		{render.Rect{X: 490, Y: 290, W: 10, H: 10}, false},
+		{render.Rect{X: 550, Y: 300, W: 10, H: 10}, false},
+	}
+	for _, test := range tests {
+		if actual := camera.Visible(test.Rect); actual != test.Visible {
+			t.Errorf("Visible(%s): expected %t, got %t", test.Rect, test.Visible, actual)
+		}
+	}
+
+	// Zooming keeps the world pixel under the cursor in place.
+	var cursor = render.NewPoint(60, 40)
+	anchor := camera.ScreenToWorld(cursor)
+	camera.ZoomAt(4, cursor)
+	if actual := camera.ScreenToWorld(cursor); actual != anchor {
+		t.Errorf("ZoomAt: expected %s under the cursor, got %s", anchor, actual)
+	}
+}
+
+func TestCameraDraw(t *testing.T) {
+	var (
+		e        = software.New(40, 40)
+		recorder = record.New(e)
+		camera   = render.NewCamera(render.Rect{X: 10, Y: 10, W: 20, H: 20})
+	)
+	camera.Position = render.NewPoint(100, 100)
+	camera.Zoom = 2
+	e.Clear(render.White)
+
+	camera.Draw(recorder, func(world render.Engine) {
+		world.DrawBox(render.Red, render.Rect{X: 102, Y: 102, W: 2, H: 2})
+		world.DrawBox(render.Blue, render.Rect{X: 0, Y: 0, W: 10, H: 10}) // culled
+		world.DrawBox(render.Green, render.Rect{X: 108, Y: 90, W: 100, H: 100})
+	})
+
+	var drawn int
+	for _, cmd := range recorder.List().Frames[0] {
+		if cmd.Op == record.OpDrawBox {
+			drawn++
+		}
+	}
+	if drawn != 2 {
+		t.Errorf("expected the box out of view to be culled, but %d of 3 were drawn", drawn)
+	}
+
+	var pixels = []struct {
+		X, Y   int
+		Expect render.Color
+	}{
+		{14, 14, render.Red},
+		{17, 17, render.Red},
+		{18, 18, render.White},
+		{26, 10, render.Green},
+		{29, 29, render.Green},
+		{30, 30, render.White}, // clipped to the viewport
+	}
+	for _, test := range pixels {
+		if actual := render.FromColor(e.Image().At(test.X, test.Y)); actual != test.Expect {
+			t.Errorf("pixel at %d,%d: expected %s, got %s", test.X, test.Y, test.Expect, actual)
+		}
+	}
+}
+
+func TestCameraDrawTransform(t *testing.T) {
+	var (
+		e        = software.New(40, 40)
+		recorder = record.New(e)
+		camera   = render.NewCamera(render.Rect{X: 0, Y: 0, W: 20, H: 20})
+	)
+	camera.Position = render.NewPoint(100, 100)
+	e.Clear(render.White)
+
+	// The box is out of view in its own coordinates, but not after the
+	// translation pushed by the draw function.
+	camera.Draw(recorder, func(world render.Engine) {
+		world.PushTransform(render.Translation(100, 100))
+		world.DrawBox(render.Red, render.Rect{X: 2, Y: 2, W: 4, H: 4})
+		world.DrawBox(render.Blue, render.Rect{X: 50, Y: 50, W: 4, H: 4}) // culled
+		world.PopTransform()
+	})
+
+	var drawn int
+	for _, cmd := range recorder.List().Frames[0] {
+		if cmd.Op == record.OpDrawBox {
+			drawn++
+		}
+	}
+	if drawn != 1 {
+		t.Errorf("expected only the box in view to be drawn, but %d of 2 were drawn", drawn)
+	}
+	if actual := render.FromColor(e.Image().At(3, 3)); actual != render.Red {
+		t.Errorf("pixel at 3,3: expected %s, got %s", render.Red, actual)
+	}
+}
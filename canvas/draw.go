@@ -2,6 +2,7 @@ package canvas
 
 import (
 	"fmt"
+	"math"
 	"syscall/js"
 
 	"git.kirsle.net/go/render"
@@ -20,9 +21,20 @@ func RGBA(c render.Color) string {
 }
 
 // Clear the canvas to a certain color.
+//
+// Like SDL, the whole canvas is cleared regardless of the clip rect.
 func (e *Engine) Clear(color render.Color) {
+	if e.stateSaved {
+		e.canvas.ctx2d.Call("restore")
+		e.stateSaved = false
+	}
+
+	e.canvas.ctx2d.Set("globalCompositeOperation", "source-over")
+	e.canvas.ctx2d.Set("globalAlpha", 1)
 	e.canvas.ctx2d.Set("fillStyle", RGBA(color))
 	e.canvas.ctx2d.Call("fillRect", 0, 0, e.width, e.height)
+
+	e.applyState()
 }
 
 // SetTitle sets the window title.
@@ -33,29 +45,37 @@ func (e *Engine) SetTitle(title string) {
 // DrawPoint draws a pixel.
 func (e *Engine) DrawPoint(color render.Color, point render.Point) {
 	e.canvas.ctx2d.Set("fillStyle", RGBA(color))
-	e.canvas.ctx2d.Call("fillRect",
-		int(point.X),
-		int(point.Y),
-		1,
-		1,
-	)
+	e.transform.Current().Boxes(render.Rect{X: point.X, Y: point.Y, W: 1, H: 1}, e.fillRect)
 }
 
 // DrawLine draws a line between two points.
 func (e *Engine) DrawLine(color render.Color, a, b render.Point) {
 	e.canvas.ctx2d.Set("fillStyle", RGBA(color))
-	for pt := range render.IterLine(a, b) {
-		e.canvas.ctx2d.Call("fillRect",
-			int(pt.X),
-			int(pt.Y),
-			1,
-			1,
-		)
+
+	m := e.transform.Current()
+	if offset, ok := m.Offset(); ok {
+		a.Add(offset)
+		b.Add(offset)
+		render.LinePoints(a, b)(func(pt render.Point) bool {
+			e.fillRect(render.Rect{X: pt.X, Y: pt.Y, W: 1, H: 1})
+			return true
+		})
+		return
 	}
+	m.LineBoxes(a, b, e.fillRect)
 }
 
 // DrawRect draws a rectangle.
 func (e *Engine) DrawRect(color render.Color, rect render.Rect) {
+	m := e.transform.Current()
+	offset, ok := m.Offset()
+	if !ok || e.blendMode == render.BlendNone {
+		e.canvas.ctx2d.Set("fillStyle", RGBA(color))
+		m.OutlineBoxes(rect, e.fillRect)
+		return
+	}
+
+	rect = rect.AddPoint(offset)
 	e.canvas.ctx2d.Set("strokeStyle", RGBA(color))
 	e.canvas.ctx2d.Call("strokeRect",
 		int(rect.X),
@@ -68,6 +88,108 @@ func (e *Engine) DrawRect(color render.Color, rect render.Rect) {
 // DrawBox draws a filled rectangle.
 func (e *Engine) DrawBox(color render.Color, rect render.Rect) {
 	e.canvas.ctx2d.Set("fillStyle", RGBA(color))
+	e.transform.Current().Boxes(rect, e.fillRect)
+}
+
+// DrawLineAA draws an anti-aliased line.
+//
+// The line is drawn pixel by pixel rather than with the context's own
+// stroke, so its coverage matches the other engines. Under a transform, the
+// endpoints are transformed and the line is drawn smoothly on the screen.
+func (e *Engine) DrawLineAA(color render.Color, x1, y1, x2, y2 float64) {
+	m := e.transform.Current()
+	x1, y1 = m.Apply(x1, y1)
+	x2, y2 = m.Apply(x2, y2)
+	render.WuLinePoints(x1, y1, x2, y2)(func(pt render.Point, coverage float64) bool {
+		e.canvas.ctx2d.Set("fillStyle", RGBA(color.ScaleAlpha(coverage)))
+		e.fillRect(render.Rect{X: pt.X, Y: pt.Y, W: 1, H: 1})
+		return true
+	})
+}
+
+// DrawPolyline draws connected lines with a stroke style.
+//
+// The stroke is filled in row by row rather than with the context's own
+// stroke, so it covers the same pixels as on the other engines.
+func (e *Engine) DrawPolyline(color render.Color, style render.StrokeStyle, points []render.Point) {
+	e.fillSpans(color, style.Spans(points))
+}
+
+// DrawPolygon draws the outline of a polygon.
+func (e *Engine) DrawPolygon(color render.Color, points []render.Point) {
+	e.fillSpans(color, render.PolygonOutline(points))
+}
+
+// FillPolygon draws a filled polygon, using the scanline rasterizer of the
+// render package so it covers the same pixels as on the other engines.
+func (e *Engine) FillPolygon(color render.Color, points []render.Point, rule render.FillRule) {
+	e.fillSpans(color, render.PolygonSpans(points, rule))
+}
+
+// DrawEllipse draws the outline of the ellipse that fits inside the rect.
+func (e *Engine) DrawEllipse(color render.Color, rect render.Rect) {
+	e.fillSpans(color, render.EllipseOutline(rect))
+}
+
+// FillEllipse draws the filled ellipse that fits inside the rect.
+func (e *Engine) FillEllipse(color render.Color, rect render.Rect) {
+	e.fillSpans(color, render.EllipseSpans(rect))
+}
+
+// DrawCircle draws the outline of a circle around a center pixel.
+func (e *Engine) DrawCircle(color render.Color, center render.Point, radius int) {
+	e.DrawEllipse(color, render.Rect{
+		X: center.X - radius,
+		Y: center.Y - radius,
+		W: radius*2 + 1,
+		H: radius*2 + 1,
+	})
+}
+
+// DrawArc draws part of the outline of the ellipse that fits inside the rect,
+// going clockwise from the start to the end angle in radians.
+func (e *Engine) DrawArc(color render.Color, rect render.Rect, start, end float64) {
+	e.fillSpans(color, render.ArcOutline(rect, start, end))
+}
+
+// DrawQuadBezier draws a quadratic Bezier curve from A to C, bending towards
+// the control point B.
+func (e *Engine) DrawQuadBezier(color render.Color, a, b, c render.Point) {
+	e.drawPoints(color, render.QuadBezierPoints(a, b, c))
+}
+
+// DrawCubicBezier draws a cubic Bezier curve from A to D, with control points
+// B and C.
+func (e *Engine) DrawCubicBezier(color render.Color, a, b, c, d render.Point) {
+	e.drawPoints(color, render.CubicBezierPoints(a, b, c, d))
+}
+
+// drawPoints draws every pixel of a shape under the current transform.
+func (e *Engine) drawPoints(color render.Color, seq func(yield func(render.Point) bool)) {
+	e.canvas.ctx2d.Set("fillStyle", RGBA(color))
+
+	m := e.transform.Current()
+	seq(func(pt render.Point) bool {
+		m.Boxes(render.Rect{X: pt.X, Y: pt.Y, W: 1, H: 1}, e.fillRect)
+		return true
+	})
+}
+
+// fillSpans fills rows of pixels under the current transform.
+func (e *Engine) fillSpans(color render.Color, spans []render.Span) {
+	e.canvas.ctx2d.Set("fillStyle", RGBA(color))
+
+	m := e.transform.Current()
+	for _, span := range spans {
+		m.Boxes(span.Rect(), e.fillRect)
+	}
+}
+
+// fillRect fills a rect on screen with the current fillStyle.
+func (e *Engine) fillRect(rect render.Rect) {
+	if e.blendMode == render.BlendNone {
+		e.clearRect(rect)
+	}
 	e.canvas.ctx2d.Call("fillRect",
 		int(rect.X),
 		int(rect.Y),
@@ -75,3 +197,116 @@ func (e *Engine) DrawBox(color render.Color, rect render.Rect) {
 		int(rect.H),
 	)
 }
+
+// clearRect makes a rect on screen transparent, as the first step of drawing
+// with BlendNone.
+//
+// The context's "copy" operation would replace the pixels too, but it clears
+// the whole canvas outside of the shape drawn, so the pixels are cleared and
+// then blended over instead.
+func (e *Engine) clearRect(rect render.Rect) {
+	e.canvas.ctx2d.Call("clearRect",
+		int(rect.X),
+		int(rect.Y),
+		int(rect.W),
+		int(rect.H),
+	)
+}
+
+// PushClip limits drawing to the inside of the rect, intersected with any
+// clip already pushed.
+func (e *Engine) PushClip(rect render.Rect) {
+	e.clip.Push(rect)
+	e.applyState()
+}
+
+// PopClip restores the clip rect that was current before the last PushClip.
+func (e *Engine) PopClip() {
+	e.clip.Pop()
+	e.applyState()
+}
+
+// PushTransform transforms the coordinates of the drawing functions by the
+// matrix, combined with any transform already pushed.
+func (e *Engine) PushTransform(m render.Matrix) {
+	e.transform.Push(m)
+}
+
+// PopTransform restores the transform that was current before the last
+// PushTransform.
+func (e *Engine) PopTransform() {
+	e.transform.Pop()
+}
+
+// SetBlendMode sets how the drawing functions mix colors with the canvas.
+func (e *Engine) SetBlendMode(mode render.BlendMode) {
+	e.blendMode = mode
+	e.applyBlend()
+}
+
+// SetOpacity scales the alpha of everything drawn, from 0 to 1.
+func (e *Engine) SetOpacity(opacity float64) {
+	e.opacity = math.Max(0, math.Min(1, opacity))
+	e.applyBlend()
+}
+
+// applyBlend sets the 2D context's composite operation and global alpha to
+// match the blend mode and opacity.
+//
+// The canvas has no modulate mode, so BlendModulate uses "multiply" like
+// BlendMultiply, which is the same for opaque colors.
+func (e *Engine) applyBlend() {
+	var op = "source-over"
+	switch e.blendMode {
+	case render.BlendAdditive:
+		op = "lighter"
+	case render.BlendModulate, render.BlendMultiply:
+		op = "multiply"
+	}
+
+	e.canvas.ctx2d.Set("globalCompositeOperation", op)
+	e.canvas.ctx2d.Set("globalAlpha", e.opacity)
+}
+
+// withTransform runs a function with the 2D context transformed by the
+// current matrix.
+//
+// Shapes are transformed in Go so they cover the same pixels as the other
+// engines; the context's own transform is only used for text and images.
+func (e *Engine) withTransform(fn func()) {
+	var (
+		ctx = e.canvas.ctx2d
+		m   = e.transform.Current()
+	)
+	if m.IsIdentity() {
+		fn()
+		return
+	}
+
+	ctx.Call("setTransform", m.A, m.B, m.C, m.D, m.E, m.F)
+	fn()
+	ctx.Call("setTransform", 1, 0, 0, 1, 0, 0)
+}
+
+// applyState sets the 2D context's clip path and blending to match the
+// current drawing state. A clip path can only be removed by restoring a saved
+// context, so the state is always applied on top of a fresh save().
+func (e *Engine) applyState() {
+	var ctx = e.canvas.ctx2d
+
+	if e.stateSaved {
+		ctx.Call("restore")
+		e.stateSaved = false
+	}
+
+	if rect, ok := e.clip.Current(); ok {
+		ctx.Call("save")
+		e.stateSaved = true
+
+		ctx.Call("beginPath")
+		ctx.Call("rect", rect.X, rect.Y, rect.W, rect.H)
+		ctx.Call("clip")
+	}
+
+	e.applyBlend()
+}
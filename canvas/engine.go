@@ -4,6 +4,7 @@ import (
 	"syscall/js"
 	"time"
 
+	"git.kirsle.net/go/render"
 	"git.kirsle.net/go/render/event"
 )
 
@@ -21,6 +22,13 @@ type Engine struct {
 	running  bool
 	textures map[string]*Texture // cached texture PNG images
 
+	// Drawing state.
+	clip       render.ClipStack
+	transform  render.TransformStack
+	stateSaved bool // the 2D context has a save() to restore
+	blendMode  render.BlendMode
+	opacity    float64
+
 	// Event channel. WASM subscribes to events asynchronously using the
 	// JavaScript APIs, whereas SDL2 polls the event queue which orders them
 	// all up for processing. This channel will order and queue the events.
@@ -39,6 +47,7 @@ func New(canvasID string) (*Engine, error) {
 		height:    canvas.ClientH(),
 		queue:     make(chan Event, 1024),
 		textures:  map[string]*Texture{},
+		opacity:   1,
 	}
 
 	return engine, nil
@@ -36,6 +36,14 @@ func (e *Engine) DrawText(text render.Text, point render.Point) error {
 
 	e.canvas.ctx2d.Set("textBaseline", "top")
 
+	e.withTransform(func() {
+		e.drawText(text, point)
+	})
+	return nil
+}
+
+// drawText draws the text with its stroke and shadow.
+func (e *Engine) drawText(text render.Text, point render.Point) {
 	write := func(dx, dy int, color render.Color) {
 		e.canvas.ctx2d.Set("fillStyle", color.ToHex())
 		e.canvas.ctx2d.Call("fillText",
@@ -65,8 +73,6 @@ func (e *Engine) DrawText(text render.Text, point render.Point) error {
 
 	// Draw the text itself.
 	write(0, 0, text.Color)
-
-	return nil
 }
 
 // ComputeTextRect computes and returns a Rect for how large the text would
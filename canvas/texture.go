@@ -98,7 +98,12 @@ func (e *Engine) Copy(t render.Texturer, src, dist render.Rect) {
 	tex := t.(*Texture)
 
 	// e.canvas.ctx2d.Call("drawImage", tex.image, dist.X, dist.Y)
-	e.canvas.ctx2d.Call("drawImage", tex.canvas, dist.X, dist.Y)
+	e.withTransform(func() {
+		if e.blendMode == render.BlendNone {
+			e.canvas.ctx2d.Call("clearRect", dist.X, dist.Y, tex.width, tex.height)
+		}
+		e.canvas.ctx2d.Call("drawImage", tex.canvas, dist.X, dist.Y)
+	})
 }
 
 // FreeTextures flushes the texture cache.
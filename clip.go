@@ -0,0 +1,44 @@
+package render
+
+// ClipStack keeps track of the nested clip rects pushed on an Engine.
+//
+// Each pushed rect is intersected with the one beneath it, so the current
+// clip is always the area common to all of them. Engine implementations use
+// it for their PushClip and PopClip functions.
+//
+// The zero value is an empty stack, meaning no clipping.
+type ClipStack struct {
+	stack []Rect
+}
+
+// Push a clip rect onto the stack and return the new current clip.
+func (s *ClipStack) Push(rect Rect) Rect {
+	rect = rect.Normalize()
+	if len(s.stack) > 0 {
+		rect = s.stack[len(s.stack)-1].Intersection(rect)
+	}
+	s.stack = append(s.stack, rect)
+	return rect
+}
+
+// Pop the top clip rect off the stack. It returns the clip that is current
+// afterwards, and false if no clipping remains.
+func (s *ClipStack) Pop() (Rect, bool) {
+	if len(s.stack) > 0 {
+		s.stack = s.stack[:len(s.stack)-1]
+	}
+	return s.Current()
+}
+
+// Current returns the current clip rect, or false if no clip is set.
+func (s *ClipStack) Current() (Rect, bool) {
+	if len(s.stack) == 0 {
+		return Rect{}, false
+	}
+	return s.stack[len(s.stack)-1], true
+}
+
+// Len returns the number of clip rects on the stack.
+func (s *ClipStack) Len() int {
+	return len(s.stack)
+}
@@ -0,0 +1,41 @@
+package render_test
+
+import (
+	"testing"
+
+	"git.kirsle.net/go/render"
+)
+
+func TestClipStack(t *testing.T) {
+	var stack render.ClipStack
+
+	if _, ok := stack.Current(); ok {
+		t.Errorf("a new ClipStack should have no clip")
+	}
+
+	// Nested clips intersect.
+	stack.Push(render.Rect{X: 0, Y: 0, W: 100, H: 100})
+	actual := stack.Push(render.Rect{X: 50, Y: -20, W: 100, H: 40})
+	if expect := (render.Rect{X: 50, Y: 0, W: 50, H: 20}); actual != expect {
+		t.Errorf("nested clip: expected %s, got %s", expect, actual)
+	}
+
+	// A clip outside the current one leaves nothing drawable.
+	if actual := stack.Push(render.Rect{X: 200, Y: 200, W: 10, H: 10}); !actual.IsEmpty() {
+		t.Errorf("disjoint clip: expected an empty rect, got %s", actual)
+	}
+
+	// Pop back to the outer clips.
+	stack.Pop()
+	if actual, ok := stack.Pop(); !ok || actual != (render.Rect{X: 0, Y: 0, W: 100, H: 100}) {
+		t.Errorf("after Pop: expected the first clip rect, got %s (ok=%t)", actual, ok)
+	}
+	if _, ok := stack.Pop(); ok || stack.Len() != 0 {
+		t.Errorf("after popping everything: expected no clip")
+	}
+
+	// Popping an empty stack is harmless.
+	if _, ok := stack.Pop(); ok {
+		t.Errorf("popping an empty stack: expected no clip")
+	}
+}
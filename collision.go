@@ -0,0 +1,210 @@
+package render
+
+import "math"
+
+// Hit describes where a ray, line segment or moving box first touches a
+// rect.
+type Hit struct {
+	// Time is how far along the movement the hit happens, as a fraction of
+	// the direction or velocity: 0 is at the start and 1 is at the end.
+	Time float64
+
+	// Point is where the hit happens. For a moving box, it's the position
+	// of the box (its X,Y) at the moment it touches.
+	Point PointF
+
+	// Normal is the side of the rect that was hit, pointing out of it: a
+	// box landing on top of a platform gets a Normal of 0,-1. It's zero if
+	// the movement started out inside of the rect already.
+	Normal Point
+}
+
+// RaycastRect finds where a ray from an origin, going in a direction, first
+// enters a rect. The Time of the hit is in units of the direction, so a hit
+// at Time 2 is twice the direction's length away from the origin.
+//
+// A ray that starts inside the rect hits it at Time 0. A ray that only runs
+// along the rect's edge or grazes a corner doesn't hit it.
+func RaycastRect(origin, direction PointF, rect RectF) (Hit, bool) {
+	rect = rect.Normalize()
+
+	var (
+		nearX, farX, normalX, okX = slab(origin.X, direction.X, rect.X, rect.X+rect.W)
+		nearY, farY, normalY, okY = slab(origin.Y, direction.Y, rect.Y, rect.Y+rect.H)
+	)
+	if !okX || !okY {
+		return Hit{}, false
+	}
+
+	var (
+		near = math.Max(nearX, nearY)
+		far  = math.Min(farX, farY)
+	)
+	if near >= far || far <= 0 {
+		return Hit{}, false
+	}
+
+	// Already inside.
+	if near < 0 {
+		return Hit{Point: origin}, true
+	}
+
+	// The side that was hit is the last one the ray crossed. Rays into an
+	// exact corner hit the top or bottom, so that a box landing on the
+	// corner of a platform stands on it.
+	var normal = Point{Y: normalY}
+	if nearX > nearY {
+		normal = Point{X: normalX}
+	}
+	return Hit{
+		Time:   near,
+		Point:  origin.Add(direction.Scale(near)),
+		Normal: normal,
+	}, true
+}
+
+// SegmentRect finds where the line segment from A to B first enters a rect.
+// The Time of the hit goes from 0 at A to 1 at B.
+func SegmentRect(a, b PointF, rect RectF) (Hit, bool) {
+	hit, ok := RaycastRect(a, b.Sub(a), rect)
+	if !ok || hit.Time > 1 {
+		return Hit{}, false
+	}
+	return hit, true
+}
+
+// SweepRect finds where a box moving by a velocity first touches a target
+// rect, checking the whole path of the box so that fast objects can't
+// tunnel through thin walls between frames. The Time of the hit goes from
+// 0 at the box's position to 1 after moving the full velocity.
+//
+// To move up to the wall, add the velocity times the Time to the box. To
+// slide along it, zero the part of the velocity along the Normal and sweep
+// the rest of the movement again. A box that only touches the target (like
+// one standing on the ground) hits it when moving into it, but not when
+// moving along or away from it.
+func SweepRect(box RectF, velocity PointF, target RectF) (Hit, bool) {
+	box = box.Normalize()
+	target = target.Normalize()
+
+	// Sweeping a box is the same as casting a ray from its corner against
+	// the target grown by the box's size.
+	var grown = RectF{
+		X: target.X - box.W,
+		Y: target.Y - box.H,
+		W: target.W + box.W,
+		H: target.H + box.H,
+	}
+	hit, ok := RaycastRect(box.Point(), velocity, grown)
+	if !ok || hit.Time > 1 {
+		return Hit{}, false
+	}
+	return hit, true
+}
+
+// GridRayPoints iterates over the cells of a grid that the line from A to B
+// passes through, in order, like LinePoints does for pixels. Cell X,Y covers
+// the pixels from X*cellSize to (X+1)*cellSize-1.
+//
+// Every cell the line touches is visited, stepping to a horizontal or
+// vertical neighbor each time, so a line can't slip diagonally between two
+// solid cells.
+func GridRayPoints(a, b PointF, cellSize int) func(yield func(Point) bool) {
+	return func(yield func(Point) bool) {
+		gridRay(a, b, cellSize, func(cell Point, _ Hit) bool {
+			return yield(cell)
+		})
+	}
+}
+
+// GridRaycast walks the cells of a grid from A to B, like GridRayPoints, and
+// returns the first cell that is solid along with where the line enters it.
+// The line can start inside of a solid cell, which hits at Time 0.
+func GridRaycast(a, b PointF, cellSize int, solid func(cell Point) bool) (Point, Hit, bool) {
+	var (
+		result Point
+		hit    Hit
+		found  bool
+	)
+	gridRay(a, b, cellSize, func(cell Point, h Hit) bool {
+		if solid(cell) {
+			result, hit, found = cell, h, true
+			return false
+		}
+		return true
+	})
+	return result, hit, found
+}
+
+// gridRay walks the grid cells from A to B with the algorithm of Amanatides
+// and Woo, calling fn with each cell and where the line enters it.
+func gridRay(a, b PointF, cellSize int, fn func(Point, Hit) bool) {
+	if cellSize < 1 {
+		cellSize = 1
+	}
+
+	var (
+		size = float64(cellSize)
+		d    = b.Sub(a)
+		cell = Point{X: int(math.Floor(a.X / size)), Y: int(math.Floor(a.Y / size))}
+		end  = Point{X: int(math.Floor(b.X / size)), Y: int(math.Floor(b.Y / size))}
+		n    = AbsInt(end.X-cell.X) + AbsInt(end.Y-cell.Y)
+
+		stepX, maxX, deltaX = gridAxis(a.X, d.X, cell.X, size)
+		stepY, maxY, deltaY = gridAxis(a.Y, d.Y, cell.Y, size)
+	)
+	if !fn(cell, Hit{Point: a}) {
+		return
+	}
+
+	for i := 0; i < n; i++ {
+		var hit Hit
+		if maxX < maxY {
+			cell.X += stepX
+			hit = Hit{Time: maxX, Normal: Point{X: -stepX}}
+			maxX += deltaX
+		} else {
+			cell.Y += stepY
+			hit = Hit{Time: maxY, Normal: Point{Y: -stepY}}
+			maxY += deltaY
+		}
+		hit.Point = a.Add(d.Scale(hit.Time))
+
+		if !fn(cell, hit) {
+			return
+		}
+	}
+}
+
+// gridAxis returns the step direction along one axis of a grid ray, the time
+// it first crosses a cell boundary and the time between crossings.
+func gridAxis(origin, d float64, cell int, size float64) (int, float64, float64) {
+	switch {
+	case d > 0:
+		return 1, (float64(cell+1)*size - origin) / d, size / d
+	case d < 0:
+		return -1, (float64(cell)*size - origin) / d, -size / d
+	}
+	return 0, math.Inf(1), math.Inf(1)
+}
+
+// slab returns when a ray along one axis is between min and max, and the
+// normal of the side it enters from. A ray that doesn't move along the axis
+// is between them always, if it's strictly inside, or never.
+func slab(origin, d, min, max float64) (near, far float64, normal int, ok bool) {
+	if d == 0 {
+		if origin <= min || origin >= max {
+			return 0, 0, 0, false
+		}
+		return math.Inf(-1), math.Inf(1), 0, true
+	}
+
+	near = (min - origin) / d
+	far = (max - origin) / d
+	normal = -1
+	if d < 0 {
+		near, far = far, near
+		normal = 1
+	}
+	return near, far, normal, true
+}
@@ -0,0 +1,134 @@
+package render_test
+
+import (
+	"math"
+	"testing"
+
+	"git.kirsle.net/go/render"
+)
+
+func TestRaycastRect(t *testing.T) {
+	var (
+		p    = render.NewPointF
+		rect = render.RectF{X: 10, Y: 10, W: 10, H: 10}
+	)
+	var tests = []struct {
+		Name      string
+		Origin    render.PointF
+		Direction render.PointF
+		Hit       bool
+		Time      float64
+		Normal    render.Point
+	}{
+		{"from the left", p(0, 15), p(1, 0), true, 10, render.NewPoint(-1, 0)},
+		{"from below", p(15, 30), p(0, -5), true, 2, render.NewPoint(0, 1)},
+		{"diagonal onto the top", p(5, 0), p(1, 1), true, 10, render.NewPoint(0, -1)},
+		{"into the corner", p(0, 0), p(1, 1), true, 10, render.NewPoint(0, -1)},
+		{"going away", p(0, 15), p(-1, 0), false, 0, render.Point{}},
+		{"passing by", p(0, 25), p(1, 0), false, 0, render.Point{}},
+		{"along the edge", p(0, 10), p(1, 0), false, 0, render.Point{}},
+		{"grazing the corner", p(0, 20), p(1, -1), false, 0, render.Point{}},
+		{"from inside", p(15, 15), p(1, 0), true, 0, render.Point{}},
+		{"standing still", p(0, 15), p(0, 0), false, 0, render.Point{}},
+	}
+	for _, test := range tests {
+		hit, ok := render.RaycastRect(test.Origin, test.Direction, rect)
+		if ok != test.Hit {
+			t.Errorf("%s: expected hit %v, got %v", test.Name, test.Hit, ok)
+			continue
+		}
+		if ok && (math.Abs(hit.Time-test.Time) > 1e-9 || hit.Normal != test.Normal) {
+			t.Errorf("%s: expected a hit at %g on side %s, got %g on side %s",
+				test.Name, test.Time, test.Normal, hit.Time, hit.Normal,
+			)
+		}
+	}
+
+	// The segment stops short of the rect.
+	if _, ok := render.SegmentRect(p(0, 15), p(9, 15), rect); ok {
+		t.Errorf("SegmentRect: expected a short segment to miss")
+	}
+	if hit, ok := render.SegmentRect(p(0, 15), p(20, 15), rect); !ok || hit.Time != 0.5 || hit.Point != p(10, 15) {
+		t.Errorf("SegmentRect: expected a hit at 10,15, got %v (%v)", hit, ok)
+	}
+}
+
+func TestSweepRect(t *testing.T) {
+	var (
+		p      = render.NewPointF
+		player = render.RectF{X: 0, Y: 0, W: 8, H: 16}
+		wall   = render.RectF{X: 100, Y: -50, W: 2, H: 100}
+		ground = render.RectF{X: -50, Y: 16, W: 100, H: 10}
+	)
+
+	// Moving so fast that the player would skip over the wall in a frame.
+	hit, ok := render.SweepRect(player, p(500, 0), wall)
+	if !ok || hit.Normal != render.NewPoint(-1, 0) || hit.Point != p(92, 0) {
+		t.Errorf("fast move: expected to stop at 92,0 against the wall, got %v (%v)", hit, ok)
+	}
+	if expect := 92.0 / 500; math.Abs(hit.Time-expect) > 1e-9 {
+		t.Errorf("fast move: expected time %g, got %g", expect, hit.Time)
+	}
+
+	// Standing on the ground: falling is stopped, walking and jumping aren't.
+	var tests = []struct {
+		Name     string
+		Velocity render.PointF
+		Hit      bool
+	}{
+		{"falling", p(0, 4), true},
+		{"walking", p(4, 0), false},
+		{"jumping", p(2, -10), false},
+		{"not moving", p(0, 0), false},
+	}
+	for _, test := range tests {
+		hit, ok := render.SweepRect(player, test.Velocity, ground)
+		if ok != test.Hit {
+			t.Errorf("%s: expected hit %v, got %v", test.Name, test.Hit, ok)
+		} else if ok && (hit.Time != 0 || hit.Normal != render.NewPoint(0, -1)) {
+			t.Errorf("%s: expected to stand on the ground, got %v", test.Name, hit)
+		}
+	}
+
+	// Too slow to reach the wall.
+	if _, ok := render.SweepRect(player, p(10, 0), wall); ok {
+		t.Errorf("slow move: expected not to reach the wall")
+	}
+}
+
+func TestGridRaycast(t *testing.T) {
+	var p = render.NewPointF
+
+	// A shallow line across 16px cells.
+	var cells []render.Point
+	render.GridRayPoints(p(8, 8), p(56, 24), 16)(func(cell render.Point) bool {
+		cells = append(cells, cell)
+		return true
+	})
+	var expect = []render.Point{{X: 0, Y: 0}, {X: 1, Y: 0}, {X: 1, Y: 1}, {X: 2, Y: 1}, {X: 3, Y: 1}}
+	if len(cells) != len(expect) {
+		t.Fatalf("GridRayPoints: expected %v, got %v", expect, cells)
+	}
+	for i := range cells {
+		if cells[i] != expect[i] {
+			t.Errorf("GridRayPoints: expected %v, got %v", expect, cells)
+			break
+		}
+	}
+
+	// Going left, into a solid tile.
+	var solid = func(cell render.Point) bool {
+		return cell == render.NewPoint(-2, 0)
+	}
+	cell, hit, ok := render.GridRaycast(p(8, 8), p(-100, 8), 16, solid)
+	if !ok || cell != render.NewPoint(-2, 0) {
+		t.Fatalf("GridRaycast: expected to hit -2,0, got %s (%v)", cell, ok)
+	}
+	if hit.Point != p(-16, 8) || hit.Normal != render.NewPoint(1, 0) {
+		t.Errorf("GridRaycast: expected to enter at -16,8 from the right, got %v", hit)
+	}
+
+	if _, _, ok := render.GridRaycast(p(8, 8), p(8, 100), 16, solid); ok {
+		t.Errorf("GridRaycast: expected a miss going down")
+	}
+}
@@ -5,16 +5,19 @@ import (
 	"errors"
 	"fmt"
 	"image/color"
+	"math"
 	"regexp"
 	"strconv"
 )
 
 var (
-	// Regexps to parse hex color codes. Three formats are supported:
+	// Regexps to parse hex color codes. Four formats are supported:
 	// * reHexColor3 uses only 3 hex characters, like #F90
+	// * reHexColor4 is the short 3 plus alpha channel, like #F90F
 	// * reHexColor6 uses standard 6 characters, like #FF9900
 	// * reHexColor8 is the standard 6 plus alpha channel, like #FF9900FF
 	reHexColor3 = regexp.MustCompile(`^([A-Fa-f0-9])([A-Fa-f0-9])([A-Fa-f0-9])$`)
+	reHexColor4 = regexp.MustCompile(`^([A-Fa-f0-9])([A-Fa-f0-9])([A-Fa-f0-9])([A-Fa-f0-9])$`)
 	reHexColor6 = regexp.MustCompile(`^([A-Fa-f0-9]{2})([A-Fa-f0-9]{2})([A-Fa-f0-9]{2})$`)
 	reHexColor8 = regexp.MustCompile(`^([A-Fa-f0-9]{2})([A-Fa-f0-9]{2})([A-Fa-f0-9]{2})([A-Fa-f0-9]{2})$`)
 )
@@ -107,16 +110,14 @@ func HexColor(hex string) (Color, error) {
 	var m []string
 	if len(hex) == 3 {
 		m = reHexColor3.FindStringSubmatch(hex)
-		// Double up the hex characters.
-		m[1] += m[1]
-		m[2] += m[2]
-		m[3] += m[3]
+	} else if len(hex) == 4 {
+		m = reHexColor4.FindStringSubmatch(hex)
 	} else if len(hex) == 6 {
 		m = reHexColor6.FindStringSubmatch(hex)
 	} else if len(hex) == 8 {
 		m = reHexColor8.FindStringSubmatch(hex)
 	} else {
-		return c, errors.New("not a valid length for color code; only 3, 6 and 8 supported")
+		return c, errors.New("not a valid length for color code; only 3, 4, 6 and 8 supported")
 	}
 
 	// Any luck?
@@ -124,6 +125,13 @@ func HexColor(hex string) (Color, error) {
 		return c, errors.New("not a valid hex color code")
 	}
 
+	// Double up the hex characters of the short forms.
+	if len(hex) < 6 {
+		for i := 1; i < len(m); i++ {
+			m[i] += m[i]
+		}
+	}
+
 	// Parse the color values. 16=base, 8=bit size
 	red, _ := strconv.ParseUint(m[1], 16, 8)
 	green, _ := strconv.ParseUint(m[2], 16, 8)
@@ -173,12 +181,10 @@ func (c Color) Transparent() bool {
 	return c.Alpha == 0x00
 }
 
-// MarshalJSON serializes the Color for JSON.
+// MarshalJSON serializes the Color for JSON as a hex code, like MarshalText.
 func (c Color) MarshalJSON() ([]byte, error) {
-	return []byte(fmt.Sprintf(
-		`"#%02x%02x%02x"`,
-		c.Red, c.Green, c.Blue,
-	)), nil
+	text, _ := c.MarshalText()
+	return json.Marshal(string(text))
 }
 
 // UnmarshalJSON reloads the Color from JSON.
@@ -189,15 +195,47 @@ func (c *Color) UnmarshalJSON(b []byte) error {
 		return err
 	}
 
-	parsed, err := HexColor(hex)
+	return c.UnmarshalText([]byte(hex))
+}
+
+// MarshalText serializes the Color as a hex code: #rrggbb for opaque colors
+// and #rrggbbaa with the alpha channel otherwise, so nothing is lost. It also
+// lets colors be used as keys of maps encoded to JSON.
+func (c Color) MarshalText() ([]byte, error) {
+	if c.Alpha == 255 {
+		return []byte(c.ToHex()), nil
+	}
+	return []byte(fmt.Sprintf(
+		"#%02x%02x%02x%02x",
+		c.Red, c.Green, c.Blue, c.Alpha,
+	)), nil
+}
+
+// UnmarshalText parses a Color written in any syntax that ParseColor
+// understands.
+func (c *Color) UnmarshalText(text []byte) error {
+	parsed, err := ParseColor(string(text))
 	if err != nil {
 		return err
 	}
 
-	c.Red = parsed.Red
-	c.Blue = parsed.Blue
-	c.Green = parsed.Green
-	c.Alpha = parsed.Alpha
+	*c = parsed
+	return nil
+}
+
+// MarshalBinary serializes the Color as four bytes: red, green, blue and
+// alpha.
+func (c Color) MarshalBinary() ([]byte, error) {
+	return []byte{c.Red, c.Green, c.Blue, c.Alpha}, nil
+}
+
+// UnmarshalBinary reloads the Color from the four bytes of MarshalBinary.
+func (c *Color) UnmarshalBinary(data []byte) error {
+	if len(data) != 4 {
+		return fmt.Errorf("Color.UnmarshalBinary: expected 4 bytes, got %d", len(data))
+	}
+
+	*c = RGBA(data[0], data[1], data[2], data[3])
 	return nil
 }
 
@@ -262,3 +300,15 @@ func (c Color) SetAlpha(v uint8) Color {
 	c.Alpha = v
 	return c
 }
+
+// ScaleAlpha multiplies the alpha value by a factor between 0 and 1, such as
+// the coverage of an anti-aliased pixel.
+func (c Color) ScaleAlpha(v float64) Color {
+	if v <= 0 {
+		v = 0
+	} else if v > 1 {
+		v = 1
+	}
+	c.Alpha = uint8(math.Round(float64(c.Alpha) * v))
+	return c
+}
@@ -0,0 +1,97 @@
+package render_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"git.kirsle.net/go/render"
+)
+
+func TestColorUnmarshal(t *testing.T) {
+	var theme struct {
+		Background render.Color
+		Foreground render.Color
+		Border     render.Color
+	}
+	err := json.Unmarshal([]byte(`{
+		"Background": "#ff9900",
+		"Foreground": "rgba(0, 0, 0, 50%)",
+		"Border": "skyblue"
+	}`), &theme)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if theme.Background != render.RGBA(255, 153, 0, 255) ||
+		theme.Foreground != render.RGBA(0, 0, 0, 128) ||
+		theme.Border != render.RGBA(135, 206, 235, 255) {
+		t.Errorf("unexpected colors: %+v", theme)
+	}
+
+	if err := json.Unmarshal([]byte(`{"Border": "nope"}`), &theme); err == nil {
+		t.Errorf("expected an error for an invalid color")
+	}
+
+	var c render.Color
+	if err := c.UnmarshalText([]byte("hsl(120, 100%, 25%)")); err != nil || c != render.RGBA(0, 128, 0, 255) {
+		t.Errorf("UnmarshalText: expected green, got %s (%v)", c, err)
+	}
+}
+
+func TestColorMarshal(t *testing.T) {
+	var tests = []struct {
+		Color  render.Color
+		Expect string
+	}{
+		{render.RGBA(255, 153, 0, 255), "#ff9900"},
+		{render.RGBA(255, 153, 0, 128), "#ff990080"},
+		{render.Invisible, "#00000000"},
+	}
+	for _, test := range tests {
+		text, _ := test.Color.MarshalText()
+		if string(text) != test.Expect {
+			t.Errorf("MarshalText: expected %s, got %s", test.Expect, text)
+		}
+
+		var actual render.Color
+		if err := actual.UnmarshalText(text); err != nil || actual != test.Color {
+			t.Errorf("UnmarshalText(%s): expected %s, got %s (%v)", text, test.Color, actual, err)
+		}
+
+		data, _ := test.Color.MarshalBinary()
+		actual = render.Color{}
+		if err := actual.UnmarshalBinary(data); err != nil || actual != test.Color {
+			t.Errorf("UnmarshalBinary: expected %s, got %s (%v)", test.Color, actual, err)
+		}
+	}
+
+	var c render.Color
+	if err := c.UnmarshalBinary([]byte{1, 2, 3}); err == nil {
+		t.Errorf("UnmarshalBinary: expected an error for 3 bytes")
+	}
+
+	// JSON round trip, including colors as map keys.
+	var palette = map[render.Color]string{
+		render.RGBA(255, 0, 0, 255): "red",
+		render.RGBA(0, 0, 0, 64):    "shadow",
+	}
+	data, err := json.Marshal(palette)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if string(data) != `{"#00000040":"shadow","#ff0000":"red"}` {
+		t.Errorf("unexpected JSON: %s", data)
+	}
+
+	var reloaded map[render.Color]string
+	if err := json.Unmarshal(data, &reloaded); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if len(reloaded) != 2 || reloaded[render.RGBA(0, 0, 0, 64)] != "shadow" {
+		t.Errorf("expected the palette back, got %v", reloaded)
+	}
+
+	data, _ = json.Marshal(render.RGBA(1, 2, 3, 4))
+	if string(data) != `"#01020304"` {
+		t.Errorf("MarshalJSON: expected \"#01020304\", got %s", data)
+	}
+}
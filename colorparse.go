@@ -0,0 +1,188 @@
+package render
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+
+	"golang.org/x/image/colornames"
+)
+
+// ParseColor parses a color written in CSS notation. It understands:
+//
+//   - Hex codes of 3, 4, 6 or 8 digits, like #F90, #F90C or #FF9900CC. The #
+//     is optional.
+//   - rgb() and rgba() with channels from 0 to 255 or as percentages, like
+//     rgb(255, 153, 0) or rgba(100%, 60%, 0%, 0.8).
+//   - hsl() and hsla() with a hue in degrees (or with a deg, rad, grad or turn
+//     unit) and percentages, like hsl(36, 100%, 50%).
+//   - hwb() with a hue and the whiteness and blackness, like hwb(36 0% 0%).
+//   - The CSS named colors, like "orange" or "rebeccapurple", and
+//     "transparent".
+//
+// Functions accept the comma separated and the newer space separated syntax
+// with a slash before the alpha, like rgb(255 153 0 / 80%). The alpha is a
+// number from 0 to 1 or a percentage. Names and functions are not case
+// sensitive.
+func ParseColor(value string) (Color, error) {
+	var text = strings.ToLower(strings.TrimSpace(value))
+	if text == "" {
+		return Invisible, fmt.Errorf("'%s': not a valid color", value)
+	}
+
+	// Named colors.
+	switch text {
+	case "transparent":
+		return Invisible, nil
+	case "rebeccapurple":
+		return RGBA(102, 51, 153, 255), nil
+	}
+	if c, ok := colornames.Map[text]; ok {
+		return RGBA(c.R, c.G, c.B, c.A), nil
+	}
+
+	// Color functions.
+	if open := strings.IndexByte(text, '('); open > 0 && strings.HasSuffix(text, ")") {
+		c, ok := parseColorFunc(
+			strings.TrimSpace(text[:open]),
+			text[open+1:len(text)-1],
+		)
+		if !ok {
+			return Invisible, fmt.Errorf("'%s': not a valid color", value)
+		}
+		return c, nil
+	}
+
+	// Hex codes.
+	c, err := HexColor(text)
+	if err != nil {
+		return c, fmt.Errorf("'%s': %s", value, err)
+	}
+	return c, nil
+}
+
+// parseColorFunc parses the arguments of a CSS color function.
+func parseColorFunc(name, args string) (Color, bool) {
+	var (
+		values = colorArgs(args)
+		alpha  = uint8(255)
+		ok     = len(values) == 3 || len(values) == 4
+	)
+	if !ok {
+		return Invisible, false
+	}
+	if len(values) == 4 {
+		alpha, ok = parseAlpha(values[3])
+	}
+
+	switch name {
+	case "rgb", "rgba":
+		r, okR := parseChannel(values[0])
+		g, okG := parseChannel(values[1])
+		b, okB := parseChannel(values[2])
+		return RGBA(r, g, b, alpha), ok && okR && okG && okB
+	case "hsl", "hsla", "hwb":
+		h, okH := parseHue(values[0])
+		x, okX := parsePercent(values[1])
+		y, okY := parsePercent(values[2])
+		ok = ok && okH && okX && okY
+		if name == "hwb" {
+			return HWBA(h, x, y, alpha), ok
+		}
+		return HSLA(h, x, y, alpha), ok
+	}
+	return Invisible, false
+}
+
+// colorArgs splits the arguments of a color function, either separated by
+// commas or by spaces with an optional "/ alpha" at the end. It returns nil
+// if they're malformed.
+func colorArgs(args string) []string {
+	var values []string
+	if strings.Contains(args, ",") {
+		values = strings.Split(args, ",")
+		for i, v := range values {
+			values[i] = strings.TrimSpace(v)
+		}
+	} else {
+		values = strings.Fields(strings.Replace(args, "/", " / ", -1))
+		if len(values) == 5 && values[3] == "/" {
+			values = append(values[:3], values[4])
+		} else if len(values) != 3 {
+			return nil
+		}
+	}
+
+	for _, v := range values {
+		if v == "" || v == "/" {
+			return nil
+		}
+	}
+	return values
+}
+
+// parseNumber parses a number with an optional percent sign.
+func parseNumber(value string) (v float64, percent bool, ok bool) {
+	if strings.HasSuffix(value, "%") {
+		value = value[:len(value)-1]
+		percent = true
+	}
+
+	v, err := strconv.ParseFloat(value, 64)
+	if err != nil || math.IsNaN(v) || math.IsInf(v, 0) {
+		return 0, false, false
+	}
+	return v, percent, true
+}
+
+// parseChannel parses a red, green or blue channel from 0 to 255 or as a
+// percentage. Values out of range are clamped, like in CSS.
+func parseChannel(value string) (uint8, bool) {
+	v, percent, ok := parseNumber(value)
+	if percent {
+		v = v / 100 * 255
+	}
+	return uint8(math.Round(math.Max(0, math.Min(255, v)))), ok
+}
+
+// parseAlpha parses an alpha channel from 0 to 1 or as a percentage.
+func parseAlpha(value string) (uint8, bool) {
+	v, percent, ok := parseNumber(value)
+	if percent {
+		v /= 100
+	}
+	return uint8(math.Round(clamp01(v) * 255)), ok
+}
+
+// parsePercent parses a percentage as a fraction from 0 to 1. A number
+// without the percent sign is a percentage too, like in CSS Color 4.
+func parsePercent(value string) (float64, bool) {
+	v, _, ok := parseNumber(value)
+	return v / 100, ok
+}
+
+// parseHue parses a hue angle into degrees. It's in degrees without a unit.
+func parseHue(value string) (float64, bool) {
+	var units = []struct {
+		Suffix  string
+		Degrees float64
+	}{
+		{"deg", 1},
+		{"grad", 360.0 / 400},
+		{"rad", 180 / math.Pi},
+		{"turn", 360},
+	}
+
+	var scale float64 = 1
+	for _, unit := range units {
+		if strings.HasSuffix(value, unit.Suffix) {
+			value = value[:len(value)-len(unit.Suffix)]
+			scale = unit.Degrees
+			break
+		}
+	}
+
+	v, percent, ok := parseNumber(value)
+	return v * scale, ok && !percent
+}
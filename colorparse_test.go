@@ -0,0 +1,82 @@
+package render_test
+
+import (
+	"testing"
+
+	"git.kirsle.net/go/render"
+)
+
+func TestParseColor(t *testing.T) {
+	var orange = render.RGBA(255, 153, 0, 255)
+
+	var tests = []struct {
+		In     string
+		Expect render.Color
+	}{
+		// Hex codes.
+		{"#F90", orange},
+		{"f90", orange},
+		{"#F90C", render.RGBA(255, 153, 0, 204)},
+		{"#FF9900", orange},
+		{"#ff990080", render.RGBA(255, 153, 0, 128)},
+
+		// Names.
+		{"orange", render.RGBA(255, 165, 0, 255)},
+		{" CornflowerBlue ", render.RGBA(100, 149, 237, 255)},
+		{"rebeccapurple", render.RGBA(102, 51, 153, 255)},
+		{"transparent", render.Invisible},
+
+		// rgb()
+		{"rgb(255, 153, 0)", orange},
+		{"RGB(255,153,0)", orange},
+		{"rgb(100%, 60%, 0%)", orange},
+		{"rgba(255, 153, 0, 0.5)", render.RGBA(255, 153, 0, 128)},
+		{"rgba(255, 153, 0, 50%)", render.RGBA(255, 153, 0, 128)},
+		{"rgb(255 153 0)", orange},
+		{"rgb(255 153 0 / 0.25)", render.RGBA(255, 153, 0, 64)},
+		{"rgb(255 153 0/25%)", render.RGBA(255, 153, 0, 64)},
+		{"rgb(300, -20, 0)", render.RGBA(255, 0, 0, 255)},
+
+		// hsl() and hwb()
+		{"hsl(36, 100%, 50%)", orange},
+		{"hsl(36deg 100% 50%)", orange},
+		{"hsl(0.1turn, 100%, 50%)", orange},
+		{"hsl(40grad 100% 50%)", orange},
+		{"hsla(396, 100%, 50%, 0)", render.RGBA(255, 153, 0, 0)},
+		{"hsl(210 50 40 / 1)", render.RGBA(51, 102, 153, 255)},
+		{"hwb(36 0% 0%)", orange},
+		{"hwb(0 50% 50%)", render.RGBA(128, 128, 128, 255)},
+	}
+	for _, test := range tests {
+		actual, err := render.ParseColor(test.In)
+		if err != nil {
+			t.Errorf("%s: unexpected error: %s", test.In, err)
+			continue
+		}
+		if actual != test.Expect {
+			t.Errorf("%s: expected %s, got %s", test.In, test.Expect, actual)
+		}
+	}
+
+	var invalid = []string{
+		"",
+		"#",
+		"#ggg",
+		"#12345",
+		"notacolor",
+		"rgb()",
+		"rgb(1, 2)",
+		"rgb(1, 2, 3, 4, 5)",
+		"rgb(1, , 3)",
+		"rgb(1 2 3 0.5)",
+		"rgb(a, b, c)",
+		"rgb(1, 2, 3",
+		"hsl(10%, 50%, 50%)",
+		"cmyk(1, 2, 3)",
+	}
+	for _, in := range invalid {
+		if c, err := render.ParseColor(in); err == nil {
+			t.Errorf("%s: expected an error, got %s", in, c)
+		}
+	}
+}
@@ -0,0 +1,192 @@
+package render
+
+import "math"
+
+// HSLA creates a Color from hue, saturation and lightness, like the CSS
+// hsl() function. The hue is an angle in degrees around the color wheel (0
+// is red, 120 green and 240 blue) and saturation and lightness go from 0 to
+// 1.
+func HSLA(h, s, l float64, a uint8) Color {
+	s = clamp01(s)
+	l = clamp01(l)
+	var c = (1 - math.Abs(2*l-1)) * s
+	return hueColor(h, c, l-c/2, a)
+}
+
+// HSVA creates a Color from hue, saturation and value (also called HSB for
+// brightness), the model of most color pickers. The hue is in degrees, and
+// saturation and value go from 0 to 1.
+func HSVA(h, s, v float64, a uint8) Color {
+	s = clamp01(s)
+	v = clamp01(v)
+	var c = v * s
+	return hueColor(h, c, v-c, a)
+}
+
+// HWBA creates a Color from hue, whiteness and blackness, like the CSS hwb()
+// function: the pure hue mixed with white and black. The hue is in degrees,
+// and whiteness and blackness go from 0 to 1. If they add up to more than 1,
+// the result is a grey.
+func HWBA(h, w, b float64, a uint8) Color {
+	w = clamp01(w)
+	b = clamp01(b)
+	if w+b >= 1 {
+		var grey = uint8(math.Round(w / (w + b) * 255))
+		return RGBA(grey, grey, grey, a)
+	}
+
+	var v = 1 - b
+	return HSVA(h, 1-w/v, v, a)
+}
+
+// HSL returns the hue, saturation and lightness of the color. The hue is in
+// degrees from 0 to 360 and is zero for greys.
+func (c Color) HSL() (h, s, l float64) {
+	var r, g, b, max, min = c.unitRGB()
+	l = (max + min) / 2
+	if max == min {
+		return 0, 0, l
+	}
+
+	var d = max - min
+	return unitHue(r, g, b, max, d), d / (1 - math.Abs(2*l-1)), l
+}
+
+// HSV returns the hue, saturation and value of the color. The hue is in
+// degrees from 0 to 360 and is zero for greys.
+func (c Color) HSV() (h, s, v float64) {
+	var r, g, b, max, min = c.unitRGB()
+	if max == min {
+		return 0, 0, max
+	}
+
+	var d = max - min
+	return unitHue(r, g, b, max, d), d / max, max
+}
+
+// HWB returns the hue, whiteness and blackness of the color. The hue is in
+// degrees from 0 to 360 and is zero for greys.
+func (c Color) HWB() (h, w, b float64) {
+	h, _, _ = c.HSV()
+	var _, _, _, max, min = c.unitRGB()
+	return h, min, 1 - max
+}
+
+// Saturate makes the color more vivid by adding to its HSL saturation,
+// keeping its hue and lightness. An amount of 0.1 adds 10%.
+func (c Color) Saturate(amount float64) Color {
+	h, s, l := c.HSL()
+	return HSLA(h, s+amount, l, c.Alpha)
+}
+
+// Desaturate makes the color duller, towards grey, by taking away from its
+// HSL saturation.
+func (c Color) Desaturate(amount float64) Color {
+	return c.Saturate(-amount)
+}
+
+// RotateHue turns the color's hue around the color wheel by an angle in
+// degrees, keeping its saturation and lightness.
+func (c Color) RotateHue(degrees float64) Color {
+	h, s, l := c.HSL()
+	return HSLA(h+degrees, s, l, c.Alpha)
+}
+
+// Complement returns the color on the opposite side of the color wheel.
+func (c Color) Complement() Color {
+	return c.RotateHue(180)
+}
+
+// Mix blends the color with another one. The weight is how much of the other
+// color to use, from 0 (this color) to 1 (the other color); 0.5 is an even
+// mix. Mixing with White or Black gives a lighter or darker shade of the
+// same hue.
+func (c Color) Mix(other Color, weight float64) Color {
+	weight = clamp01(weight)
+	mix := func(a, b uint8) uint8 {
+		return uint8(math.Round(float64(a) + (float64(b)-float64(a))*weight))
+	}
+	return Color{
+		Red:   mix(c.Red, other.Red),
+		Green: mix(c.Green, other.Green),
+		Blue:  mix(c.Blue, other.Blue),
+		Alpha: mix(c.Alpha, other.Alpha),
+	}
+}
+
+// unitRGB returns the color's channels from 0 to 1, and the largest and
+// smallest of them.
+func (c Color) unitRGB() (r, g, b, max, min float64) {
+	r = float64(c.Red) / 255
+	g = float64(c.Green) / 255
+	b = float64(c.Blue) / 255
+	return r, g, b, math.Max(r, math.Max(g, b)), math.Min(r, math.Min(g, b))
+}
+
+// unitHue returns the hue in degrees of an RGB color whose largest channel
+// is max and whose range is d.
+func unitHue(r, g, b, max, d float64) float64 {
+	var h float64
+	switch max {
+	case r:
+		h = (g - b) / d
+	case g:
+		h = (b-r)/d + 2
+	default:
+		h = (r-g)/d + 4
+	}
+
+	h *= 60
+	if h < 0 {
+		h += 360
+	}
+	return h
+}
+
+// hueColor returns the color of a hue with a chroma (the range between the
+// largest and smallest channel) added to a minimum channel m.
+func hueColor(h, chroma, m float64, a uint8) Color {
+	h = math.Mod(h, 360)
+	if h < 0 {
+		h += 360
+	}
+
+	var (
+		x       = chroma * (1 - math.Abs(math.Mod(h/60, 2)-1))
+		r, g, b float64
+	)
+	switch {
+	case h < 60:
+		r, g, b = chroma, x, 0
+	case h < 120:
+		r, g, b = x, chroma, 0
+	case h < 180:
+		r, g, b = 0, chroma, x
+	case h < 240:
+		r, g, b = 0, x, chroma
+	case h < 300:
+		r, g, b = x, 0, chroma
+	default:
+		r, g, b = chroma, 0, x
+	}
+
+	channel := func(v float64) uint8 {
+		return uint8(math.Round(clamp01(v+m) * 255))
+	}
+	return Color{
+		Red:   channel(r),
+		Green: channel(g),
+		Blue:  channel(b),
+		Alpha: a,
+	}
+}
+
+// clamp01 limits a value to between 0 and 1.
+func clamp01(v float64) float64 {
+	if v < 0 {
+		return 0
+	} else if v > 1 {
+		return 1
+	}
+	return v
+}
@@ -0,0 +1,107 @@
+package render_test
+
+import (
+	"math"
+	"testing"
+
+	"git.kirsle.net/go/render"
+)
+
+func TestColorSpaces(t *testing.T) {
+	var tests = []struct {
+		Color   render.Color
+		H, S, L float64 // HSL
+		SV, V   float64 // HSV saturation and value
+		W, B    float64 // HWB
+	}{
+		{render.RGBA(255, 0, 0, 255), 0, 1, 0.5, 1, 1, 0, 0},
+		{render.RGBA(0, 255, 0, 255), 120, 1, 0.5, 1, 1, 0, 0},
+		{render.RGBA(0, 0, 255, 255), 240, 1, 0.5, 1, 1, 0, 0},
+		{render.RGBA(255, 255, 255, 255), 0, 0, 1, 0, 1, 1, 0},
+		{render.RGBA(0, 0, 0, 255), 0, 0, 0, 0, 0, 0, 1},
+		{render.RGBA(255, 128, 0, 255), 30.12, 1, 0.5, 1, 1, 0, 0},
+		{render.RGBA(51, 102, 153, 255), 210, 0.5, 0.4, 2.0 / 3, 0.6, 0.2, 0.4},
+	}
+
+	near := func(a, b float64) bool {
+		return math.Abs(a-b) < 0.01
+	}
+
+	for _, test := range tests {
+		h, s, l := test.Color.HSL()
+		if !near(h, test.H) || !near(s, test.S) || !near(l, test.L) {
+			t.Errorf("%s HSL: expected %v,%v,%v, got %v,%v,%v", test.Color, test.H, test.S, test.L, h, s, l)
+		}
+
+		h, s, v := test.Color.HSV()
+		if !near(h, test.H) || !near(s, test.SV) || !near(v, test.V) {
+			t.Errorf("%s HSV: expected %v,%v,%v, got %v,%v,%v", test.Color, test.H, test.SV, test.V, h, s, v)
+		}
+
+		h, w, b := test.Color.HWB()
+		if !near(h, test.H) || !near(w, test.W) || !near(b, test.B) {
+			t.Errorf("%s HWB: expected %v,%v,%v, got %v,%v,%v", test.Color, test.H, test.W, test.B, h, w, b)
+		}
+	}
+
+	// Every color survives a round trip through each model.
+	for r := 0; r < 256; r += 15 {
+		for g := 0; g < 256; g += 15 {
+			for b := 0; b < 256; b += 15 {
+				var c = render.RGBA(uint8(r), uint8(g), uint8(b), 128)
+
+				h, s, l := c.HSL()
+				if actual := render.HSLA(h, s, l, c.Alpha); actual != c {
+					t.Fatalf("HSL round trip: expected %s, got %s", c, actual)
+				}
+				h, s, v := c.HSV()
+				if actual := render.HSVA(h, s, v, c.Alpha); actual != c {
+					t.Fatalf("HSV round trip: expected %s, got %s", c, actual)
+				}
+				h, w, bl := c.HWB()
+				if actual := render.HWBA(h, w, bl, c.Alpha); actual != c {
+					t.Fatalf("HWB round trip: expected %s, got %s", c, actual)
+				}
+			}
+		}
+	}
+}
+
+func TestColorAdjustments(t *testing.T) {
+	var (
+		base = render.RGBA(51, 102, 153, 200) // hsl(210, 50%, 40%)
+		hue  = func(c render.Color) float64 {
+			h, _, _ := c.HSL()
+			return h
+		}
+	)
+
+	var tests = []struct {
+		Name   string
+		Actual render.Color
+		Expect render.Color
+	}{
+		{"Saturate", base.Saturate(0.2), render.HSLA(210, 0.7, 0.4, 200)},
+		{"Saturate past 100%", base.Saturate(2), render.HSLA(210, 1, 0.4, 200)},
+		{"Desaturate", base.Desaturate(0.2), render.HSLA(210, 0.3, 0.4, 200)},
+		{"Desaturate to grey", base.Desaturate(1), render.RGBA(102, 102, 102, 200)},
+		{"RotateHue", base.RotateHue(30), render.HSLA(240, 0.5, 0.4, 200)},
+		{"RotateHue backwards", base.RotateHue(-240), render.HSLA(330, 0.5, 0.4, 200)},
+		{"Complement", base.Complement(), render.RGBA(153, 102, 51, 200)},
+		{"Mix", render.Black.Mix(render.White, 0.5), render.RGBA(128, 128, 128, 255)},
+		{"Mix none", base.Mix(render.White, 0), base},
+		{"Mix all", base.Mix(render.Invisible, 1), render.Invisible},
+	}
+	for _, test := range tests {
+		if test.Actual != test.Expect {
+			t.Errorf("%s: expected %s, got %s", test.Name, test.Expect, test.Actual)
+		}
+	}
+
+	// Hover and pressed shades keep the hue.
+	for _, shade := range []render.Color{base.Mix(render.White, 0.3), base.Mix(render.Black, 0.3)} {
+		if math.Abs(hue(shade)-210) > 1 {
+			t.Errorf("Mix: expected %s to keep the hue 210, got %v", shade, hue(shade))
+		}
+	}
+}
@@ -0,0 +1,175 @@
+package render
+
+import (
+	"image"
+	"math"
+)
+
+// CompositeOp is how a source color is combined with the destination color
+// under it, like the blend modes of image editors. The math follows the W3C
+// Compositing and Blending spec, with premultiplied alpha.
+type CompositeOp int
+
+// CompositeOp values. Except for CompositeAdd, they blend the colors where
+// the source and destination overlap and draw the source over the
+// destination, so a transparent source leaves the destination alone.
+const (
+	CompositeSourceOver CompositeOp = iota // the source over the destination, normal alpha blending
+	CompositeMultiply                      // multiplies the colors, darkening like layered ink
+	CompositeScreen                        // inverts, multiplies and inverts again, lightening like projected light
+	CompositeOverlay                       // multiplies dark and screens light parts of the destination, adding contrast
+	CompositeAdd                           // adds the colors and alphas, like the Canvas "lighter" operation
+	CompositeDarken                        // the darker of each channel
+	CompositeLighten                       // the lighter of each channel
+	CompositeDifference                    // the difference of each channel
+)
+
+// Composite returns the color of a source color drawn over this color with a
+// composite operation.
+func (c Color) Composite(src Color, op CompositeOp) Color {
+	var (
+		result = op.composite(src.premultiplied(), c.premultiplied())
+		alpha  = result[3]
+	)
+	if alpha <= 0 {
+		return Invisible
+	}
+
+	channel := func(v float64) uint8 {
+		return uint8(math.Round(clamp01(v/alpha) * 255))
+	}
+	return Color{
+		Red:   channel(result[0]),
+		Green: channel(result[1]),
+		Blue:  channel(result[2]),
+		Alpha: uint8(math.Round(alpha * 255)),
+	}
+}
+
+// CompositeImage draws a source image over an image with a composite
+// operation, for flattening layers into one image. The top-left corner of
+// the source's bounds lands at a point of the destination, and the parts
+// outside the destination's bounds are clipped.
+func CompositeImage(dst *image.RGBA, src image.Image, at Point, op CompositeOp) {
+	var (
+		sb     = src.Bounds()
+		offset = image.Pt(at.X-sb.Min.X, at.Y-sb.Min.Y)
+		r      = sb.Add(offset).Intersect(dst.Bounds())
+	)
+
+	// Read premultiplied pixels straight from the common image types.
+	var srcAt func(x, y int) [4]float64
+	switch m := src.(type) {
+	case *image.RGBA:
+		srcAt = func(x, y int) [4]float64 {
+			var i = m.PixOffset(x, y)
+			return unitPixel(m.Pix[i], m.Pix[i+1], m.Pix[i+2], m.Pix[i+3])
+		}
+	case *image.NRGBA:
+		srcAt = func(x, y int) [4]float64 {
+			var i = m.PixOffset(x, y)
+			return RGBA(m.Pix[i], m.Pix[i+1], m.Pix[i+2], m.Pix[i+3]).premultiplied()
+		}
+	default:
+		srcAt = func(x, y int) [4]float64 {
+			r, g, b, a := src.At(x, y).RGBA()
+			return [4]float64{
+				float64(r) / 0xffff,
+				float64(g) / 0xffff,
+				float64(b) / 0xffff,
+				float64(a) / 0xffff,
+			}
+		}
+	}
+
+	for y := r.Min.Y; y < r.Max.Y; y++ {
+		for x := r.Min.X; x < r.Max.X; x++ {
+			var s = srcAt(x-offset.X, y-offset.Y)
+			if s[3] == 0 && op != CompositeAdd {
+				continue
+			}
+
+			var (
+				pix    = dst.Pix[dst.PixOffset(x, y):]
+				result = op.composite(s, unitPixel(pix[0], pix[1], pix[2], pix[3]))
+			)
+			for i, v := range result {
+				pix[i] = uint8(math.Round(clamp01(v) * 255))
+			}
+		}
+	}
+}
+
+// composite combines premultiplied source and destination colors, with
+// channels from 0 to 1, into a premultiplied result.
+func (op CompositeOp) composite(src, dst [4]float64) [4]float64 {
+	var sa, da = src[3], dst[3]
+	if op == CompositeAdd {
+		return [4]float64{
+			math.Min(1, src[0]+dst[0]),
+			math.Min(1, src[1]+dst[1]),
+			math.Min(1, src[2]+dst[2]),
+			math.Min(1, sa+da),
+		}
+	}
+
+	var result [4]float64
+	for i := 0; i < 3; i++ {
+		var cs, cd = src[i], dst[i]
+		result[i] = cs*(1-da) + cd*(1-sa)
+		if sa > 0 && da > 0 {
+			// The blend function works on the colors without their alpha.
+			result[i] += sa * da * op.blend(cd/da, cs/sa)
+		}
+	}
+	result[3] = sa + da*(1-sa)
+	return result
+}
+
+// blend mixes the backdrop (destination) and source channels of the
+// separable blend modes.
+func (op CompositeOp) blend(b, s float64) float64 {
+	switch op {
+	case CompositeMultiply:
+		return b * s
+	case CompositeScreen:
+		return b + s - b*s
+	case CompositeOverlay:
+		// Hard light with the layers swapped.
+		if b <= 0.5 {
+			return s * 2 * b
+		}
+		var b2 = 2*b - 1
+		return s + b2 - s*b2
+	case CompositeDarken:
+		return math.Min(b, s)
+	case CompositeLighten:
+		return math.Max(b, s)
+	case CompositeDifference:
+		return math.Abs(b - s)
+	default:
+		return s
+	}
+}
+
+// premultiplied returns the color's channels from 0 to 1, multiplied by its
+// alpha.
+func (c Color) premultiplied() [4]float64 {
+	var a = float64(c.Alpha) / 255
+	return [4]float64{
+		float64(c.Red) / 255 * a,
+		float64(c.Green) / 255 * a,
+		float64(c.Blue) / 255 * a,
+		a,
+	}
+}
+
+// unitPixel returns the channels of a pixel from 0 to 1.
+func unitPixel(r, g, b, a uint8) [4]float64 {
+	return [4]float64{
+		float64(r) / 255,
+		float64(g) / 255,
+		float64(b) / 255,
+		float64(a) / 255,
+	}
+}
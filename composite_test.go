@@ -0,0 +1,118 @@
+package render_test
+
+import (
+	"image"
+	"image/color"
+	"testing"
+
+	"git.kirsle.net/go/render"
+)
+
+func TestComposite(t *testing.T) {
+	var (
+		orange = render.RGBA(255, 128, 0, 255)
+		grey   = render.RGBA(128, 128, 128, 255)
+		dark   = render.RGBA(64, 64, 64, 255)
+	)
+
+	var tests = []struct {
+		Name   string
+		Dst    render.Color
+		Src    render.Color
+		Op     render.CompositeOp
+		Expect render.Color
+	}{
+		{"over", render.White, render.RGBA(255, 0, 0, 128), render.CompositeSourceOver, render.RGBA(255, 127, 127, 255)},
+		{"over opaque", grey, orange, render.CompositeSourceOver, orange},
+		{"over transparent", render.RGBA(10, 20, 30, 100), render.Invisible, render.CompositeSourceOver, render.RGBA(10, 20, 30, 100)},
+		{"over nothing", render.Invisible, render.Invisible, render.CompositeSourceOver, render.Invisible},
+		{"translucent over translucent", render.RGBA(0, 0, 255, 128), render.RGBA(255, 0, 0, 128), render.CompositeSourceOver, render.RGBA(170, 0, 85, 192)},
+		{"multiply", grey, orange, render.CompositeMultiply, render.RGBA(128, 64, 0, 255)},
+		{"multiply onto nothing", render.Invisible, render.RGBA(10, 20, 30, 100), render.CompositeMultiply, render.RGBA(10, 20, 30, 100)},
+		{"screen", grey, orange, render.CompositeScreen, render.RGBA(255, 192, 128, 255)},
+		{"overlay", dark, orange, render.CompositeOverlay, render.RGBA(128, 64, 0, 255)},
+		{"overlay light", render.White, orange, render.CompositeOverlay, render.White},
+		{"add", render.RGBA(200, 50, 10, 255), render.RGBA(100, 50, 0, 255), render.CompositeAdd, render.RGBA(255, 100, 10, 255)},
+		{"add alpha", render.RGBA(255, 0, 0, 100), render.RGBA(255, 0, 0, 100), render.CompositeAdd, render.RGBA(255, 0, 0, 200)},
+		{"darken", grey, orange, render.CompositeDarken, render.RGBA(128, 128, 0, 255)},
+		{"lighten", grey, orange, render.CompositeLighten, render.RGBA(255, 128, 128, 255)},
+		{"difference", grey, orange, render.CompositeDifference, render.RGBA(127, 0, 128, 255)},
+		{"difference with itself", orange, orange, render.CompositeDifference, render.Black},
+	}
+	for _, test := range tests {
+		actual := test.Dst.Composite(test.Src, test.Op)
+		if actual != test.Expect {
+			t.Errorf("%s: expected %s, got %s", test.Name, test.Expect, actual)
+		}
+	}
+}
+
+func TestCompositeImage(t *testing.T) {
+	var (
+		colors = []render.Color{
+			render.RGBA(255, 0, 0, 128),
+			render.RGBA(0, 255, 0, 255),
+			render.RGBA(0, 0, 0, 0),
+		}
+		backdrop = []render.Color{
+			render.White,
+			render.RGBA(0, 0, 255, 128),
+			render.Invisible,
+			render.RGBA(30, 60, 90, 255),
+		}
+	)
+
+	// The same source as each kind of image.
+	var (
+		nrgba = image.NewNRGBA(image.Rect(5, 5, 8, 6))
+		rgba  = image.NewRGBA(image.Rect(0, 0, 3, 1))
+		gray  = image.NewGray(image.Rect(0, 0, 3, 1))
+	)
+	for i, c := range colors {
+		nrgba.Set(5+i, 5, color.NRGBA{c.Red, c.Green, c.Blue, c.Alpha})
+		rgba.Set(i, 0, color.NRGBA{c.Red, c.Green, c.Blue, c.Alpha})
+		gray.Set(i, 0, color.Gray{Y: uint8(i * 100)})
+	}
+
+	for _, op := range []render.CompositeOp{render.CompositeSourceOver, render.CompositeMultiply, render.CompositeAdd} {
+		for _, src := range []image.Image{nrgba, rgba, gray} {
+			for _, at := range []render.Point{render.NewPoint(1, 0), render.NewPoint(-1, 0)} {
+				var dst = image.NewRGBA(image.Rect(0, 0, 4, 1))
+				for i, c := range backdrop {
+					dst.Set(i, 0, color.NRGBA{c.Red, c.Green, c.Blue, c.Alpha})
+				}
+				render.CompositeImage(dst, src, at, op)
+
+				for x := 0; x < 4; x++ {
+					var (
+						sx     = src.Bounds().Min.X + x - at.X
+						expect = backdrop[x]
+					)
+					if sx >= src.Bounds().Min.X && sx < src.Bounds().Max.X {
+						expect = expect.Composite(straight(src.At(sx, src.Bounds().Min.Y)), op)
+					}
+
+					// Allow for rounding through the premultiplied pixels.
+					var actual = straight(dst.At(x, 0))
+					if !near(actual, expect) {
+						t.Errorf("op %d, %T at %s: pixel %d expected %s, got %s", op, src, at, x, expect, actual)
+					}
+				}
+			}
+		}
+	}
+}
+
+// near returns whether two colors are within rounding of each other.
+func near(a, b render.Color) bool {
+	diff := func(x, y uint8) bool {
+		return render.AbsInt(int(x)-int(y)) <= 2
+	}
+	return diff(a.Red, b.Red) && diff(a.Green, b.Green) && diff(a.Blue, b.Blue) && diff(a.Alpha, b.Alpha)
+}
+
+// straight returns a color without premultiplied alpha.
+func straight(c color.Color) render.Color {
+	var n = color.NRGBAModel.Convert(c).(color.NRGBA)
+	return render.RGBA(n.R, n.G, n.B, n.A)
+}
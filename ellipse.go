@@ -1,9 +1,26 @@
 package render
 
+import "math"
+
 // MidpointEllipse implements an ellipse plotting algorithm.
+//
+// Prefer MidpointEllipsePoints, which doesn't need a goroutine.
 func MidpointEllipse(center, radius Point) chan Point {
-	yield := make(chan Point)
-	go func() {
+	return pointChan(MidpointEllipsePoints(center, radius))
+}
+
+// MidpointEllipsePoints iterates the points of an ellipse using the midpoint
+// ellipse algorithm. It yields the same points as MidpointEllipse.
+func MidpointEllipsePoints(center, radius Point) func(yield func(Point) bool) {
+	return func(yield func(Point) bool) {
+		// A flat ellipse is a line.
+		if radius.X == 0 || radius.Y == 0 {
+			LinePoints(
+				NewPoint(center.X-radius.X, center.Y-radius.Y),
+				NewPoint(center.X+radius.X, center.Y+radius.Y),
+			)(yield)
+			return
+		}
 
 		var (
 			pos   = NewPoint(radius.X, 0)
@@ -16,11 +33,20 @@ func MidpointEllipse(center, radius Point) chan Point {
 				(radius.Y*radius.Y)/4
 		)
 
+		// Plot the four symmetrical points of the current position.
+		var lastY int
+		plot := func() bool {
+			lastY = pos.Y
+			return yield(NewPoint(center.X+pos.X, center.Y+pos.Y)) &&
+				yield(NewPoint(center.X+pos.X, center.Y-pos.Y)) &&
+				yield(NewPoint(center.X-pos.X, center.Y+pos.Y)) &&
+				yield(NewPoint(center.X-pos.X, center.Y-pos.Y))
+		}
+
 		for delta.Y < delta.X {
-			yield <- NewPoint(center.X+pos.X, center.Y+pos.Y)
-			yield <- NewPoint(center.X+pos.X, center.Y-pos.Y)
-			yield <- NewPoint(center.X-pos.X, center.Y+pos.Y)
-			yield <- NewPoint(center.X-pos.X, center.Y-pos.Y)
+			if !plot() {
+				return
+			}
 
 			pos.Y++
 
@@ -39,11 +65,10 @@ func MidpointEllipse(center, radius Point) chan Point {
 			radius.Y*radius.Y*(pos.X-1)*(pos.X-1) -
 			radius.Y*radius.Y*radius.X*radius.X
 
-		for pos.X >= 0 {
-			yield <- NewPoint(center.X+pos.X, center.Y+pos.Y)
-			yield <- NewPoint(center.X+pos.X, center.Y-pos.Y)
-			yield <- NewPoint(center.X-pos.X, center.Y+pos.Y)
-			yield <- NewPoint(center.X-pos.X, center.Y-pos.Y)
+		for pos.X >= 0 && pos.Y <= radius.Y {
+			if !plot() {
+				return
+			}
 
 			pos.X--
 
@@ -58,7 +83,146 @@ func MidpointEllipse(center, radius Point) chan Point {
 			}
 		}
 
-		close(yield)
-	}()
-	return yield
+		// Very flat ellipses run out of X before reaching the tips; finish
+		// them with a straight run so there are no gaps.
+		for pos.X, pos.Y = 0, lastY+1; pos.Y <= radius.Y; pos.Y++ {
+			if !plot() {
+				return
+			}
+		}
+	}
+}
+
+// ellipseRows returns the fill extent of each row of pixels inside the
+// ellipse that fits the rect, from its top row down. A pixel is inside when
+// its center is.
+func ellipseRows(r Rect) []Span {
+	r = r.Normalize()
+	if r.IsEmpty() {
+		return nil
+	}
+
+	var (
+		rx   = float64(r.W) / 2
+		ry   = float64(r.H) / 2
+		cx   = float64(r.X) + rx
+		cy   = float64(r.Y) + ry
+		rows = make([]Span, 0, r.H)
+	)
+	for y := r.Y; y < r.Y+r.H; y++ {
+		var dy = (float64(y) + 0.5 - cy) / ry
+		if dy*dy >= 1 {
+			continue
+		}
+
+		var (
+			dx = rx * math.Sqrt(1-dy*dy)
+			x1 = int(math.Ceil(cx - dx - 0.5))
+			x2 = int(math.Ceil(cx+dx-0.5)) - 1
+		)
+
+		// Rows near the tips of a narrow ellipse may miss every pixel
+		// center; keep the middle pixels so the ellipse reaches its rect.
+		if x2 < x1 {
+			x1 = int(math.Floor(cx - 0.5))
+			x2 = int(math.Ceil(cx - 0.5))
+		}
+		rows = append(rows, Span{Y: y, X1: x1, X2: x2})
+	}
+	return rows
+}
+
+// EllipseSpans returns the rows of pixels that fill the ellipse fitting
+// inside the rect.
+func EllipseSpans(r Rect) []Span {
+	return ellipseRows(r)
+}
+
+// EllipseOutline returns the rows of pixels that outline the ellipse fitting
+// inside the rect, one pixel wide and without gaps however flat the ellipse
+// is. The outline is the edge of the pixels filled by EllipseSpans.
+func EllipseOutline(r Rect) []Span {
+	var (
+		rows  = ellipseRows(r)
+		spans = make([]Span, 0, len(rows)*2)
+	)
+	for i, row := range rows {
+		// The interior of the row is covered by the rows above and below.
+		var x1, x2 = row.X1 + 1, row.X2 - 1
+		for _, j := range []int{i - 1, i + 1} {
+			if j < 0 || j >= len(rows) || rows[j].Y != row.Y+j-i {
+				x1, x2 = 0, -1
+				break
+			}
+			x1 = maxInt(x1, rows[j].X1)
+			x2 = minInt(x2, rows[j].X2)
+		}
+
+		if x1 > x2 {
+			spans = append(spans, row)
+			continue
+		}
+		if x1 > row.X1 {
+			spans = append(spans, Span{Y: row.Y, X1: row.X1, X2: x1 - 1})
+		}
+		if x2 < row.X2 {
+			spans = append(spans, Span{Y: row.Y, X1: x2 + 1, X2: row.X2})
+		}
+	}
+	return spans
+}
+
+// ArcOutline returns the rows of pixels that outline part of the ellipse
+// fitting inside the rect.
+//
+// The arc goes clockwise from the start angle to the end angle, in radians,
+// where zero points to the right like the HTML Canvas ellipse function. An
+// arc of 2*Pi or more is the whole ellipse.
+func ArcOutline(r Rect, start, end float64) []Span {
+	var sweep = end - start
+	if sweep >= 2*math.Pi {
+		return EllipseOutline(r)
+	}
+	sweep = math.Mod(sweep, 2*math.Pi)
+	if sweep < 0 {
+		sweep += 2 * math.Pi
+	}
+
+	r = r.Normalize()
+	var (
+		rx    = float64(r.W) / 2
+		ry    = float64(r.H) / 2
+		cx    = float64(r.X) + rx
+		cy    = float64(r.Y) + ry
+		spans []Span
+	)
+	for _, row := range EllipseOutline(r) {
+		var (
+			inArc bool
+			x1    int // start of the current run of pixels on the arc
+		)
+		for x := row.X1; x <= row.X2+1; x++ {
+			var on bool
+			if x <= row.X2 {
+				// The angle around the ellipse, as if it were a circle.
+				var angle = math.Atan2(
+					(float64(row.Y)+0.5-cy)/ry,
+					(float64(x)+0.5-cx)/rx,
+				) - start
+				angle = math.Mod(angle, 2*math.Pi)
+				if angle < 0 {
+					angle += 2 * math.Pi
+				}
+				on = angle <= sweep
+			}
+
+			if on && !inArc {
+				inArc, x1 = true, x
+			} else if !on && inArc {
+				spans = append(spans, Span{Y: row.Y, X1: x1, X2: x - 1})
+				inArc = false
+			}
+		}
+	}
+	return spans
 }
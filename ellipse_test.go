@@ -0,0 +1,101 @@
+package render_test
+
+import (
+	"math"
+	"testing"
+
+	"git.kirsle.net/go/render"
+)
+
+// connected checks that every pixel has a neighbor, including diagonals.
+func connected(pixels map[render.Point]bool) bool {
+	for pt := range pixels {
+		var found bool
+		for dy := -1; dy <= 1 && !found; dy++ {
+			for dx := -1; dx <= 1 && !found; dx++ {
+				found = (dx != 0 || dy != 0) && pixels[render.NewPoint(pt.X+dx, pt.Y+dy)]
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
+
+func TestEllipseSpans(t *testing.T) {
+	var tests = []render.Rect{
+		{X: 10, Y: 20, W: 30, H: 16},
+		{X: 0, Y: 0, W: 11, H: 11},
+		{X: -5, Y: 0, W: 200, H: 3}, // very flat
+		{X: 0, Y: 0, W: 2, H: 90},   // very narrow
+	}
+	for _, rect := range tests {
+		var (
+			fill    = spanPixels(t, render.EllipseSpans(rect))
+			outline = spanPixels(t, render.EllipseOutline(rect))
+			bounds  render.Rect
+		)
+
+		// The fill touches every side of the rect.
+		for pt := range fill {
+			bounds = bounds.Union(render.Rect{X: pt.X, Y: pt.Y, W: 1, H: 1})
+		}
+		if bounds != rect {
+			t.Errorf("ellipse in %s: fill covers %s", rect, bounds)
+		}
+
+		// The outline is part of the fill, without gaps.
+		for pt := range outline {
+			if !fill[pt] {
+				t.Errorf("ellipse in %s: outline pixel %s is outside the fill", rect, pt)
+			}
+		}
+		if !connected(outline) {
+			t.Errorf("ellipse in %s: the outline has gaps", rect)
+		}
+	}
+
+	// A quarter arc from 3 o'clock to 6 o'clock is the bottom right of the
+	// outline.
+	var (
+		rect    = render.Rect{X: 0, Y: 0, W: 21, H: 21}
+		arc     = spanPixels(t, render.ArcOutline(rect, 0, math.Pi/2))
+		outline = spanPixels(t, render.EllipseOutline(rect))
+	)
+	if !arc[render.NewPoint(20, 10)] || !arc[render.NewPoint(10, 20)] || !arc[render.NewPoint(17, 17)] {
+		t.Errorf("quarter arc: expected the bottom right of the outline")
+	}
+	if arc[render.NewPoint(0, 10)] || arc[render.NewPoint(10, 0)] || arc[render.NewPoint(3, 3)] {
+		t.Errorf("quarter arc: expected only the bottom right of the outline")
+	}
+	if len(arc) < len(outline)/4 || len(arc) > len(outline)/4+3 {
+		t.Errorf("quarter arc: expected about a quarter of %d pixels, got %d", len(outline), len(arc))
+	}
+}
+
+func TestMidpointEllipseFlat(t *testing.T) {
+	var tests = []render.Point{
+		{X: 60, Y: 1},
+		{X: 1, Y: 40},
+		{X: 2, Y: 25},
+		{X: 30, Y: 0},
+	}
+	for _, radius := range tests {
+		var pixels = map[render.Point]bool{}
+		render.MidpointEllipsePoints(render.Point{}, radius)(func(pt render.Point) bool {
+			if render.AbsInt(pt.X) > radius.X || render.AbsInt(pt.Y) > radius.Y {
+				t.Errorf("radius %s: point %s is outside the ellipse", radius, pt)
+			}
+			pixels[pt] = true
+			return true
+		})
+
+		if !pixels[render.NewPoint(0, radius.Y)] || !pixels[render.NewPoint(radius.X, 0)] {
+			t.Errorf("radius %s: expected the ellipse to reach its tips", radius)
+		}
+		if !connected(pixels) {
+			t.Errorf("radius %s: the ellipse has gaps", radius)
+		}
+	}
+}
@@ -0,0 +1,196 @@
+package render
+
+import (
+	"image"
+	"image/color"
+)
+
+// FloodOptions configures FloodFill.
+type FloodOptions struct {
+	// Diagonal makes the fill 8-connected, spreading to diagonal neighbors
+	// as well. By default the fill is 4-connected, and a diagonal line of
+	// pixels is enough to stop it.
+	Diagonal bool
+
+	// Tolerance is how far each of the red, green, blue and alpha channels
+	// of a pixel may be from the color at the seed point for the pixel to
+	// be filled. Zero fills only the exact same color.
+	Tolerance uint8
+
+	// Boundary is an optional mask of pixels the fill can't cross, such as
+	// the outlines on a separate layer. Pixels that aren't fully
+	// transparent in the mask are walls. It uses the same coordinates as
+	// the image.
+	Boundary image.Image
+}
+
+// FloodFill finds the area around a seed point that has the same color as
+// it, like a paint bucket tool or a magic wand selection, and returns it as
+// spans in order from top to bottom.
+//
+// The fill doesn't recurse, so it can fill images of any size. A seed point
+// outside of the image fills nothing.
+func FloodFill(img image.Image, seed Point, opts FloodOptions) []Span {
+	var bounds = img.Bounds()
+	if !image.Pt(seed.X, seed.Y).In(bounds) {
+		return nil
+	}
+
+	var (
+		at      = rgbaAt(img)
+		target  = at(seed.X, seed.Y)
+		width   = bounds.Dx()
+		visited = make([]bool, width*bounds.Dy())
+		wall    = boundaryAt(opts.Boundary)
+		spans   []Span
+	)
+
+	// fillable returns whether a pixel is in the image, not yet filled, not
+	// a wall and close enough to the seed color.
+	fillable := func(x, y int) bool {
+		if x < bounds.Min.X || x >= bounds.Max.X || y < bounds.Min.Y || y >= bounds.Max.Y {
+			return false
+		}
+		if visited[(y-bounds.Min.Y)*width+x-bounds.Min.X] || wall(x, y) {
+			return false
+		}
+		return colorWithin(at(x, y), target, opts.Tolerance)
+	}
+
+	// Scanline fill: take a pixel off the stack, fill the whole run of
+	// pixels around it on its row, and push the start of every run of
+	// fillable pixels touching it on the rows above and below.
+	var (
+		stack = []Point{seed}
+		reach = 0
+	)
+	if opts.Diagonal {
+		reach = 1
+	}
+	for len(stack) > 0 {
+		var pt = stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		if !fillable(pt.X, pt.Y) {
+			continue
+		}
+
+		var x1, x2 = pt.X, pt.X
+		for fillable(x1-1, pt.Y) {
+			x1--
+		}
+		for fillable(x2+1, pt.Y) {
+			x2++
+		}
+
+		var row = (pt.Y - bounds.Min.Y) * width
+		for x := x1; x <= x2; x++ {
+			visited[row+x-bounds.Min.X] = true
+		}
+		spans = append(spans, Span{Y: pt.Y, X1: x1, X2: x2})
+
+		for _, y := range []int{pt.Y - 1, pt.Y + 1} {
+			var inRun bool
+			for x := x1 - reach; x <= x2+reach; x++ {
+				if fillable(x, y) {
+					if !inRun {
+						stack = append(stack, Point{X: x, Y: y})
+					}
+					inRun = true
+				} else {
+					inRun = false
+				}
+			}
+		}
+	}
+
+	return mergeSpans(spans)
+}
+
+// FloodMask is like FloodFill, but returns the filled area as a mask the
+// size of the image that is opaque where it was filled.
+func FloodMask(img image.Image, seed Point, opts FloodOptions) *image.Alpha {
+	var mask = image.NewAlpha(img.Bounds())
+	for _, span := range FloodFill(img, seed, opts) {
+		var offset = mask.PixOffset(span.X1, span.Y)
+		for x := span.X1; x <= span.X2; x++ {
+			mask.Pix[offset] = 255
+			offset++
+		}
+	}
+	return mask
+}
+
+// FloodPaint flood fills an image with a color, replacing the pixels of the
+// area rather than blending over them, and returns the spans it painted. A
+// translucent color is stored premultiplied, like the rest of the image.
+func FloodPaint(img *image.RGBA, seed Point, c Color, opts FloodOptions) []Span {
+	var (
+		spans = FloodFill(img, seed, opts)
+		rgba  = color.RGBAModel.Convert(color.NRGBA{
+			R: c.Red,
+			G: c.Green,
+			B: c.Blue,
+			A: c.Alpha,
+		}).(color.RGBA)
+	)
+	for _, span := range spans {
+		var offset = img.PixOffset(span.X1, span.Y)
+		for x := span.X1; x <= span.X2; x++ {
+			img.Pix[offset+0] = rgba.R
+			img.Pix[offset+1] = rgba.G
+			img.Pix[offset+2] = rgba.B
+			img.Pix[offset+3] = rgba.A
+			offset += 4
+		}
+	}
+	return spans
+}
+
+// rgbaAt returns a function that reads the pixels of an image as
+// color.RGBA, reading straight from the buffer of an *image.RGBA.
+func rgbaAt(img image.Image) func(x, y int) color.RGBA {
+	if rgba, ok := img.(*image.RGBA); ok {
+		return func(x, y int) color.RGBA {
+			var pix = rgba.Pix[rgba.PixOffset(x, y):]
+			return color.RGBA{R: pix[0], G: pix[1], B: pix[2], A: pix[3]}
+		}
+	}
+	return func(x, y int) color.RGBA {
+		return color.RGBAModel.Convert(img.At(x, y)).(color.RGBA)
+	}
+}
+
+// boundaryAt returns a function that tells whether a pixel of a boundary
+// mask is a wall.
+func boundaryAt(mask image.Image) func(x, y int) bool {
+	switch m := mask.(type) {
+	case nil:
+		return func(x, y int) bool {
+			return false
+		}
+	case *image.Alpha:
+		return func(x, y int) bool {
+			return image.Pt(x, y).In(m.Rect) && m.Pix[m.PixOffset(x, y)] > 0
+		}
+	}
+	return func(x, y int) bool {
+		_, _, _, a := mask.At(x, y).RGBA()
+		return a > 0
+	}
+}
+
+// colorWithin returns whether every channel of two colors is within the
+// tolerance of each other.
+func colorWithin(a, b color.RGBA, tolerance uint8) bool {
+	return channelWithin(a.R, b.R, tolerance) &&
+		channelWithin(a.G, b.G, tolerance) &&
+		channelWithin(a.B, b.B, tolerance) &&
+		channelWithin(a.A, b.A, tolerance)
+}
+
+func channelWithin(a, b, tolerance uint8) bool {
+	if a > b {
+		return a-b <= tolerance
+	}
+	return b-a <= tolerance
+}
@@ -0,0 +1,155 @@
+package render_test
+
+import (
+	"image"
+	"image/color"
+	"testing"
+
+	"git.kirsle.net/go/render"
+)
+
+// floodImage makes a 10x10 white image with a black square outline from 2,2
+// to 7,7 and a gap in its top right corner, where a diagonal connects the
+// inside to the outside.
+func floodImage() *image.RGBA {
+	var img = image.NewRGBA(image.Rect(0, 0, 10, 10))
+	for y := 0; y < 10; y++ {
+		for x := 0; x < 10; x++ {
+			img.Set(x, y, color.White)
+		}
+	}
+	for i := 2; i <= 7; i++ {
+		img.Set(i, 2, color.Black)
+		img.Set(i, 7, color.Black)
+		img.Set(2, i, color.Black)
+		img.Set(7, i, color.Black)
+	}
+	img.Set(7, 2, color.White)
+	return img
+}
+
+func TestFloodFill(t *testing.T) {
+	var tests = []struct {
+		Name   string
+		Seed   render.Point
+		Opts   render.FloodOptions
+		Expect int // pixels filled
+	}{
+		{
+			Name:   "inside, 4-connected",
+			Seed:   render.NewPoint(4, 4),
+			Expect: 16,
+		},
+		{
+			Name:   "inside, leaking through the diagonal",
+			Seed:   render.NewPoint(4, 4),
+			Opts:   render.FloodOptions{Diagonal: true},
+			Expect: 100 - 19,
+		},
+		{
+			Name:   "the outline",
+			Seed:   render.NewPoint(2, 2),
+			Expect: 19,
+		},
+		{
+			Name:   "everything with tolerance",
+			Seed:   render.NewPoint(0, 0),
+			Opts:   render.FloodOptions{Tolerance: 255},
+			Expect: 100,
+		},
+		{
+			Name: "stopped by the boundary",
+			Seed: render.NewPoint(0, 0),
+			Opts: render.FloodOptions{
+				Boundary: &image.Uniform{C: color.Alpha{A: 255}},
+			},
+			Expect: 0,
+		},
+		{
+			Name:   "outside of the image",
+			Seed:   render.NewPoint(10, 0),
+			Expect: 0,
+		},
+	}
+	for _, test := range tests {
+		var (
+			img    = floodImage()
+			spans  = render.FloodFill(img, test.Seed, test.Opts)
+			pixels = spanPixels(t, spans)
+		)
+		if len(pixels) != test.Expect {
+			t.Errorf("%s: expected %d pixels, got %d", test.Name, test.Expect, len(pixels))
+		}
+
+		// The mask matches the spans.
+		var mask = render.FloodMask(img, test.Seed, test.Opts)
+		for y := 0; y < 10; y++ {
+			for x := 0; x < 10; x++ {
+				if filled := mask.AlphaAt(x, y).A == 255; filled != pixels[render.NewPoint(x, y)] {
+					t.Errorf("%s: mask at %d,%d is %v, spans have %v", test.Name, x, y, filled, !filled)
+				}
+			}
+		}
+	}
+}
+
+func TestFloodBoundary(t *testing.T) {
+	// A wall down the middle of a blank image, on a separate layer.
+	var (
+		img      = image.NewRGBA(image.Rect(0, 0, 8, 8))
+		boundary = image.NewAlpha(img.Bounds())
+	)
+	for y := 0; y < 8; y++ {
+		boundary.SetAlpha(4, y, color.Alpha{A: 1})
+	}
+
+	var spans = render.FloodPaint(img, render.NewPoint(0, 0), render.Red, render.FloodOptions{
+		Diagonal: true,
+		Boundary: boundary,
+	})
+	if len(spans) != 8 || spans[0] != (render.Span{Y: 0, X1: 0, X2: 3}) {
+		t.Errorf("expected 8 rows up to the wall, got %v", spans)
+	}
+	if actual := render.FromColor(img.At(3, 7)); actual != render.Red {
+		t.Errorf("pixel at 3,7: expected %s, got %s", render.Red, actual)
+	}
+	if actual := render.FromColor(img.At(4, 7)); actual != render.Invisible {
+		t.Errorf("pixel at 4,7: expected it to be untouched, got %s", actual)
+	}
+}
+
+func TestFloodPaintTranslucent(t *testing.T) {
+	// The image is premultiplied, so a translucent color is stored
+	// premultiplied too.
+	var img = image.NewRGBA(image.Rect(0, 0, 4, 4))
+	render.FloodPaint(img, render.NewPoint(0, 0), render.RGBA(255, 0, 0, 128), render.FloodOptions{})
+
+	var expect = color.RGBA{R: 128, A: 128}
+	if actual := img.RGBAAt(3, 3); actual != expect {
+		t.Errorf("expected %v, got %v", expect, actual)
+	}
+}
+
+func TestFloodLarge(t *testing.T) {
+	// A maze of walls with a gap at alternating ends, for a single corridor
+	// that winds through the whole image.
+	var img = image.NewGray(image.Rect(0, 0, 500, 500))
+	for x := 1; x < 500; x += 2 {
+		for y := 0; y < 500; y++ {
+			img.Pix[img.PixOffset(x, y)] = 255
+		}
+		if x%4 == 1 {
+			img.Pix[img.PixOffset(x, 499)] = 0
+		} else {
+			img.Pix[img.PixOffset(x, 0)] = 0
+		}
+	}
+
+	var (
+		spans  = render.FloodFill(img, render.NewPoint(0, 0), render.FloodOptions{})
+		pixels = spanPixels(t, spans)
+	)
+	if expect := 250*500 + 250; len(pixels) != expect {
+		t.Errorf("expected the corridor of %d pixels, got %d", expect, len(pixels))
+	}
+}
@@ -33,6 +33,9 @@ func ParseResolution(resi string) (int, int, error) {
 
 // TrimBox helps with Engine.Copy() to trim a destination box so that it
 // won't overflow with the parent container.
+//
+// Engine.PushClip is a simpler alternative that works for every drawing
+// function, not only Copy.
 func TrimBox(src, dst *Rect, p Point, S Rect, thickness int) {
 	// Constrain source width to not bigger than Canvas width.
 	if src.W > S.W {
@@ -114,3 +117,19 @@ func AbsInt32(v int32) int32 {
 	}
 	return v
 }
+
+// minInt returns the smaller of two integers.
+func minInt(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
+
+// maxInt returns the larger of two integers.
+func maxInt(a, b int) int {
+	if a > b {
+		return a
+	}
+	return b
+}
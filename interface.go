@@ -26,9 +26,52 @@ type Engine interface {
 	DrawLine(Color, Point, Point)
 	DrawRect(Color, Rect)
 	DrawBox(Color, Rect)
+
+	// DrawLineAA draws an anti-aliased line. The endpoints may fall between
+	// pixels; whole numbers are pixel centers.
+	DrawLineAA(c Color, x1, y1, x2, y2 float64)
+
+	// DrawPolyline draws connected lines through the points with a thick
+	// stroke. If the last point is the first, the shape is closed.
+	DrawPolyline(Color, StrokeStyle, []Point)
+
+	// Polygons of any shape, closed from the last point back to the first.
+	DrawPolygon(Color, []Point)
+	FillPolygon(Color, []Point, FillRule)
+
+	// Ellipses fit inside of a rect, and circles are centered on a pixel.
+	// Arc angles are in radians, going clockwise from the right.
+	DrawEllipse(Color, Rect)
+	FillEllipse(Color, Rect)
+	DrawCircle(c Color, center Point, radius int)
+	DrawArc(c Color, rect Rect, start, end float64)
+
+	// Bezier curves from the first point to the last, bending towards the
+	// control points between them.
+	DrawQuadBezier(Color, Point, Point, Point)
+	DrawCubicBezier(Color, Point, Point, Point, Point)
 	DrawText(Text, Point) error
 	ComputeTextRect(Text) (Rect, error)
 
+	// Clipping: while a clip rect is pushed, all drawing is limited to the
+	// area inside of it. Nested clips intersect with the ones beneath them.
+	PushClip(Rect)
+	PopClip()
+
+	// Transforms: while a transform is pushed, the coordinates given to the
+	// drawing functions (including Copy) are transformed by it. Nested
+	// transforms combine with the ones beneath them. Clip rects are not
+	// transformed and are always in screen coordinates.
+	PushTransform(Matrix)
+	PopTransform()
+
+	// Blending: the blend mode and opacity apply to everything drawn after
+	// they're set, including Copy and text, but not Clear. The opacity goes
+	// from 0 to 1 and scales the alpha of what's drawn. The defaults are
+	// BlendAlpha and an opacity of 1.
+	SetBlendMode(BlendMode)
+	SetOpacity(float64)
+
 	// Texture caching.
 	StoreTexture(name string, img image.Image) (Texturer, error)
 	LoadTexture(name string) (Texturer, error)
@@ -0,0 +1,202 @@
+package render
+
+import (
+	"image"
+	"math/bits"
+)
+
+// Mask is a bitmap of the solid pixels of a sprite or a piece of level
+// geometry, for pixel-perfect collision checks where bounding Rects aren't
+// accurate enough.
+//
+// Each row is stored as bits packed into 64-bit words, so two masks are
+// compared 64 pixels at a time.
+type Mask struct {
+	w, h   int
+	stride int // words per row
+	words  []uint64
+}
+
+// NewMask creates an empty mask of a size.
+func NewMask(width, height int) *Mask {
+	if width < 0 {
+		width = 0
+	}
+	if height < 0 {
+		height = 0
+	}
+
+	var stride = (width + 63) / 64
+	return &Mask{
+		w:      width,
+		h:      height,
+		stride: stride,
+		words:  make([]uint64, stride*height),
+	}
+}
+
+// MaskFromImage creates a mask of the pixels of an image whose alpha is above
+// a threshold. A threshold of zero makes every pixel that isn't fully
+// transparent solid.
+//
+// The mask's 0,0 is the top-left corner of the image's bounds.
+func MaskFromImage(img image.Image, threshold uint8) *Mask {
+	var (
+		bounds = img.Bounds()
+		mask   = NewMask(bounds.Dx(), bounds.Dy())
+	)
+
+	// Read the alpha straight from the common image types.
+	var alpha func(x, y int) uint8
+	switch m := img.(type) {
+	case *image.RGBA:
+		alpha = func(x, y int) uint8 {
+			return m.Pix[m.PixOffset(x, y)+3]
+		}
+	case *image.NRGBA:
+		alpha = func(x, y int) uint8 {
+			return m.Pix[m.PixOffset(x, y)+3]
+		}
+	case *image.Alpha:
+		alpha = func(x, y int) uint8 {
+			return m.Pix[m.PixOffset(x, y)]
+		}
+	default:
+		alpha = func(x, y int) uint8 {
+			_, _, _, a := img.At(x, y).RGBA()
+			return uint8(a >> 8)
+		}
+	}
+
+	for y := bounds.Min.Y; y < bounds.Max.Y; y++ {
+		for x := bounds.Min.X; x < bounds.Max.X; x++ {
+			if alpha(x, y) > threshold {
+				mask.Set(x-bounds.Min.X, y-bounds.Min.Y, true)
+			}
+		}
+	}
+	return mask
+}
+
+// MaskFromColorKey creates a mask of the pixels of an image that are not the
+// key color, for images that use a color such as magenta for their
+// background instead of transparency. Only the red, green and blue channels
+// are compared, and fully transparent pixels are never solid.
+func MaskFromColorKey(img image.Image, key Color) *Mask {
+	var (
+		bounds = img.Bounds()
+		mask   = NewMask(bounds.Dx(), bounds.Dy())
+	)
+	for y := bounds.Min.Y; y < bounds.Max.Y; y++ {
+		for x := bounds.Min.X; x < bounds.Max.X; x++ {
+			var c = FromColor(img.At(x, y))
+			if c.Alpha > 0 && (c.Red != key.Red || c.Green != key.Green || c.Blue != key.Blue) {
+				mask.Set(x-bounds.Min.X, y-bounds.Min.Y, true)
+			}
+		}
+	}
+	return mask
+}
+
+// Size returns the width and height of the mask as a Rect at 0,0.
+func (m *Mask) Size() Rect {
+	return Rect{W: m.w, H: m.h}
+}
+
+// Get returns whether a pixel is solid. Pixels outside of the mask are not.
+func (m *Mask) Get(x, y int) bool {
+	if x < 0 || y < 0 || x >= m.w || y >= m.h {
+		return false
+	}
+	return m.words[y*m.stride+x/64]&(1<<uint(x%64)) != 0
+}
+
+// Set whether a pixel is solid. Pixels outside of the mask are ignored.
+func (m *Mask) Set(x, y int, solid bool) {
+	if x < 0 || y < 0 || x >= m.w || y >= m.h {
+		return
+	}
+	var bit = uint64(1) << uint(x%64)
+	if solid {
+		m.words[y*m.stride+x/64] |= bit
+	} else {
+		m.words[y*m.stride+x/64] &^= bit
+	}
+}
+
+// Count returns the number of solid pixels.
+func (m *Mask) Count() int {
+	var n int
+	for _, word := range m.words {
+		n += bits.OnesCount64(word)
+	}
+	return n
+}
+
+// Overlaps returns whether any solid pixel of the other mask, placed at an
+// offset from this one, lands on a solid pixel of this mask.
+//
+// To check two sprites, the offset is the other sprite's position minus
+// this one's, which is what Point.Compare returns.
+func (m *Mask) Overlaps(other *Mask, offset Point) bool {
+	_, ok := m.Collision(other, offset)
+	return ok
+}
+
+// Collision returns the first solid pixel of this mask, going from top to
+// bottom and left to right, that the other mask overlaps when it's placed at
+// an offset from this one. The pixel is in this mask's coordinates; subtract
+// the offset for the other mask's.
+func (m *Mask) Collision(other *Mask, offset Point) (Point, bool) {
+	var (
+		y1 = maxInt(0, offset.Y)
+		y2 = minInt(m.h, offset.Y+other.h)
+		x1 = maxInt(0, offset.X)
+		x2 = minInt(m.w, offset.X+other.w)
+	)
+	if x1 >= x2 {
+		return Point{}, false
+	}
+
+	for y := y1; y < y2; y++ {
+		var row = m.words[y*m.stride : (y+1)*m.stride]
+		for i := x1 / 64; i <= (x2-1)/64; i++ {
+			if row[i] == 0 {
+				continue
+			}
+
+			// The bits of the other mask under this word. They're zero past
+			// the edges of both masks, so there's nothing to clip.
+			var hit = row[i] & other.bits(y-offset.Y, i*64-offset.X)
+			if hit != 0 {
+				return Point{X: i*64 + bits.TrailingZeros64(hit), Y: y}, true
+			}
+		}
+	}
+	return Point{}, false
+}
+
+// bits returns the 64 pixels of a row starting at X as a word, with the
+// pixel at X in the lowest bit. Pixels outside the mask are zero.
+func (m *Mask) bits(y, x int) uint64 {
+	if y < 0 || y >= m.h {
+		return 0
+	}
+
+	var (
+		row   = m.words[y*m.stride : (y+1)*m.stride]
+		i     = x >> 6 // rounds down for negative X too
+		shift = uint(x & 63)
+	)
+	word := func(i int) uint64 {
+		if i < 0 || i >= len(row) {
+			return 0
+		}
+		return row[i]
+	}
+
+	if shift == 0 {
+		return word(i)
+	}
+	return word(i)>>shift | word(i+1)<<(64-shift)
+}
@@ -0,0 +1,76 @@
+package render_test
+
+import (
+	"image"
+	"image/color"
+	"math/rand"
+	"testing"
+
+	"git.kirsle.net/go/render"
+)
+
+func TestMaskFromImage(t *testing.T) {
+	var img = image.NewNRGBA(image.Rect(10, 10, 14, 12))
+	img.Set(10, 10, color.NRGBA{A: 255})
+	img.Set(13, 11, color.NRGBA{R: 255, A: 100})
+	img.Set(12, 10, color.NRGBA{A: 10})
+
+	var mask = render.MaskFromImage(img, 50)
+	if size := mask.Size(); size != (render.Rect{W: 4, H: 2}) {
+		t.Errorf("Size: expected 4x2, got %s", size)
+	}
+	if !mask.Get(0, 0) || !mask.Get(3, 1) || mask.Get(2, 0) || mask.Count() != 2 {
+		t.Errorf("expected the two opaque pixels to be solid, got %d solid", mask.Count())
+	}
+	if render.MaskFromImage(img, 0).Count() != 3 {
+		t.Errorf("threshold 0: expected every visible pixel to be solid")
+	}
+
+	// Color keyed.
+	var keyed = image.NewRGBA(image.Rect(0, 0, 3, 1))
+	keyed.Set(0, 0, render.Magenta.ToColor())
+	keyed.Set(1, 0, render.Black.ToColor())
+	if mask := render.MaskFromColorKey(keyed, render.Magenta); mask.Get(0, 0) || !mask.Get(1, 0) || mask.Get(2, 0) {
+		t.Errorf("MaskFromColorKey: expected only the black pixel to be solid")
+	}
+}
+
+func TestMaskCollision(t *testing.T) {
+	var rng = rand.New(rand.NewSource(1))
+	randomMask := func(w, h int) *render.Mask {
+		var mask = render.NewMask(w, h)
+		for i := 0; i < w*h/20+1; i++ {
+			mask.Set(rng.Intn(w), rng.Intn(h), true)
+		}
+		return mask
+	}
+
+	// Compare against checking every pixel, across word boundaries.
+	for i := 0; i < 500; i++ {
+		var (
+			a      = randomMask(rng.Intn(150)+1, rng.Intn(20)+1)
+			b      = randomMask(rng.Intn(150)+1, rng.Intn(20)+1)
+			offset = render.NewPoint(rng.Intn(300)-150, rng.Intn(40)-20)
+			expect render.Point
+			found  bool
+		)
+		for y := 0; y < a.Size().H && !found; y++ {
+			for x := 0; x < a.Size().W; x++ {
+				if a.Get(x, y) && b.Get(x-offset.X, y-offset.Y) {
+					expect, found = render.NewPoint(x, y), true
+					break
+				}
+			}
+		}
+
+		actual, ok := a.Collision(b, offset)
+		if ok != found || actual != expect {
+			t.Fatalf("%s vs %s at %s: expected %s (%v), got %s (%v)",
+				a.Size(), b.Size(), offset, expect, found, actual, ok,
+			)
+		}
+		if a.Overlaps(b, offset) != found {
+			t.Fatalf("Overlaps disagrees with Collision")
+		}
+	}
+}
@@ -0,0 +1,298 @@
+package render
+
+import (
+	"fmt"
+	"math"
+)
+
+// Matrix is a 2D affine transformation. It maps a point X,Y to:
+//
+//	X' = A*X + C*Y + E
+//	Y' = B*X + D*Y + F
+//
+// The fields are in the same order as the arguments of the HTML Canvas
+// setTransform(a, b, c, d, e, f) function.
+//
+// The zero value is not useful: use Identity or one of the constructor
+// functions below.
+type Matrix struct {
+	A, B, C, D, E, F float64
+}
+
+// Identity is the matrix that leaves points unchanged.
+var Identity = Matrix{A: 1, D: 1}
+
+// Translation returns a matrix that moves points by X,Y.
+func Translation(x, y float64) Matrix {
+	return Matrix{A: 1, D: 1, E: x, F: y}
+}
+
+// Scaling returns a matrix that scales points away from the origin.
+func Scaling(sx, sy float64) Matrix {
+	return Matrix{A: sx, D: sy}
+}
+
+// Rotation returns a matrix that rotates points around the origin by an angle
+// in radians. With the Y axis pointing down, positive angles rotate clockwise
+// on screen.
+func Rotation(radians float64) Matrix {
+	var (
+		sin = math.Sin(radians)
+		cos = math.Cos(radians)
+	)
+	return Matrix{A: cos, B: sin, C: -sin, D: cos}
+}
+
+func (m Matrix) String() string {
+	return fmt.Sprintf("Matrix<%g,%g,%g,%g,%g,%g>", m.A, m.B, m.C, m.D, m.E, m.F)
+}
+
+// Multiply returns the matrix that applies the other matrix first and then
+// this one.
+func (m Matrix) Multiply(other Matrix) Matrix {
+	return Matrix{
+		A: m.A*other.A + m.C*other.B,
+		B: m.B*other.A + m.D*other.B,
+		C: m.A*other.C + m.C*other.D,
+		D: m.B*other.C + m.D*other.D,
+		E: m.A*other.E + m.C*other.F + m.E,
+		F: m.B*other.E + m.D*other.F + m.F,
+	}
+}
+
+// Translate returns the matrix with a translation applied before it, like
+// the HTML Canvas translate() function.
+func (m Matrix) Translate(x, y float64) Matrix {
+	return m.Multiply(Translation(x, y))
+}
+
+// Scale returns the matrix with a scaling applied before it.
+func (m Matrix) Scale(sx, sy float64) Matrix {
+	return m.Multiply(Scaling(sx, sy))
+}
+
+// Rotate returns the matrix with a rotation applied before it.
+func (m Matrix) Rotate(radians float64) Matrix {
+	return m.Multiply(Rotation(radians))
+}
+
+// Invert returns the inverse matrix, or false if the matrix can't be
+// inverted because it squashes everything onto a line or a point.
+func (m Matrix) Invert() (Matrix, bool) {
+	var det = m.A*m.D - m.B*m.C
+	if det == 0 {
+		return Matrix{}, false
+	}
+	return Matrix{
+		A: m.D / det,
+		B: -m.B / det,
+		C: -m.C / det,
+		D: m.A / det,
+		E: (m.C*m.F - m.D*m.E) / det,
+		F: (m.B*m.E - m.A*m.F) / det,
+	}, true
+}
+
+// IsIdentity returns whether the matrix leaves points unchanged.
+func (m Matrix) IsIdentity() bool {
+	return m == Identity
+}
+
+// IsAxisAligned returns whether the matrix has no rotation or skew, so that
+// rectangles stay upright rectangles.
+func (m Matrix) IsAxisAligned() bool {
+	return m.B == 0 && m.C == 0
+}
+
+// Offset returns the translation of the matrix rounded to whole pixels, and
+// true if translation is all the matrix does.
+func (m Matrix) Offset() (Point, bool) {
+	return NewPoint(round(m.E), round(m.F)),
+		m.A == 1 && m.B == 0 && m.C == 0 && m.D == 1
+}
+
+// Apply the matrix to an X,Y coordinate.
+func (m Matrix) Apply(x, y float64) (float64, float64) {
+	return m.A*x + m.C*y + m.E,
+		m.B*x + m.D*y + m.F
+}
+
+// TransformPoint applies the matrix to a point, rounding to the nearest
+// pixel.
+func (m Matrix) TransformPoint(p Point) Point {
+	x, y := m.Apply(float64(p.X), float64(p.Y))
+	return NewPoint(round(x), round(y))
+}
+
+// TransformRect returns the bounding box of the rect after the matrix is
+// applied to its corners.
+func (m Matrix) TransformRect(r Rect) Rect {
+	var (
+		quad       = m.quad(r)
+		minX, minY = quad[0][0], quad[0][1]
+		maxX, maxY = minX, minY
+	)
+	for _, pt := range quad[1:] {
+		minX = math.Min(minX, pt[0])
+		minY = math.Min(minY, pt[1])
+		maxX = math.Max(maxX, pt[0])
+		maxY = math.Max(maxY, pt[1])
+	}
+
+	var (
+		x1 = round(minX)
+		y1 = round(minY)
+	)
+	return Rect{
+		X: x1,
+		Y: y1,
+		W: round(maxX) - x1,
+		H: round(maxY) - y1,
+	}
+}
+
+// Boxes calls fn with the screen boxes that fill in the rect after the
+// matrix is applied to it.
+//
+// Rects are treated as covering whole pixels: with a scaled matrix, each
+// pixel becomes a larger box, which is what a zoomed in drawing looks like.
+// Axis aligned matrices produce a single box at least one pixel in size.
+// Rotated or skewed rects are filled by one box per row of pixels.
+func (m Matrix) Boxes(r Rect, fn func(Rect)) {
+	r = r.Normalize()
+	if r.IsEmpty() {
+		return
+	}
+
+	if m.IsAxisAligned() {
+		var (
+			x1, y1 = m.Apply(float64(r.X), float64(r.Y))
+			x2, y2 = m.Apply(float64(r.X+r.W), float64(r.Y+r.H))
+			box    = Rect{
+				X: round(math.Min(x1, x2)),
+				Y: round(math.Min(y1, y2)),
+				W: round(math.Max(x1, x2)) - round(math.Min(x1, x2)),
+				H: round(math.Max(y1, y2)) - round(math.Min(y1, y2)),
+			}
+		)
+
+		// Zoomed out pixels are still drawn.
+		if box.W == 0 {
+			box.W = 1
+		}
+		if box.H == 0 {
+			box.H = 1
+		}
+		fn(box)
+		return
+	}
+
+	var (
+		quad   = m.quad(r)
+		filled bool
+	)
+	fillConvex(quad[:], func(y, x1, x2 int) {
+		filled = true
+		fn(Rect{X: x1, Y: y, W: x2 - x1 + 1, H: 1})
+	})
+
+	// Shapes smaller than a pixel didn't cover any pixel centers; draw the
+	// pixel under their middle so they don't vanish.
+	if !filled {
+		cx, cy := m.Apply(float64(r.X)+float64(r.W)/2, float64(r.Y)+float64(r.H)/2)
+		fn(Rect{X: int(math.Floor(cx)), Y: int(math.Floor(cy)), W: 1, H: 1})
+	}
+}
+
+// OutlineBoxes calls fn with the screen boxes that draw the outline of the
+// rect after the matrix is applied. The outline covers the pixels from X to
+// X+W-1 and Y to Y+H-1, like Engine.DrawRect.
+func (m Matrix) OutlineBoxes(r Rect, fn func(Rect)) {
+	r = r.Normalize()
+	if r.IsEmpty() {
+		return
+	}
+
+	m.Boxes(Rect{X: r.X, Y: r.Y, W: r.W, H: 1}, fn)
+	if r.H > 1 {
+		m.Boxes(Rect{X: r.X, Y: r.Y + r.H - 1, W: r.W, H: 1}, fn)
+	}
+	if r.H > 2 {
+		m.Boxes(Rect{X: r.X, Y: r.Y + 1, W: 1, H: r.H - 2}, fn)
+		if r.W > 1 {
+			m.Boxes(Rect{X: r.X + r.W - 1, Y: r.Y + 1, W: 1, H: r.H - 2}, fn)
+		}
+	}
+}
+
+// LineBoxes calls fn with the screen boxes that draw each pixel of the line
+// from A to B after the matrix is applied.
+func (m Matrix) LineBoxes(a, b Point, fn func(Rect)) {
+	LinePoints(a, b)(func(pt Point) bool {
+		m.Boxes(Rect{X: pt.X, Y: pt.Y, W: 1, H: 1}, fn)
+		return true
+	})
+}
+
+// TransformStack keeps track of the nested transforms pushed on an Engine.
+//
+// Each pushed matrix is applied before the ones beneath it, so drawing
+// inside of a nested transform happens in its local coordinates, like the
+// HTML Canvas save/transform/restore pattern. Engine implementations use it
+// for their PushTransform and PopTransform functions.
+//
+// The zero value is an empty stack, meaning the Identity transform.
+type TransformStack struct {
+	stack []Matrix
+}
+
+// Push a matrix onto the stack and return the new current transform.
+func (s *TransformStack) Push(m Matrix) Matrix {
+	m = s.Current().Multiply(m)
+	s.stack = append(s.stack, m)
+	return m
+}
+
+// Pop the top matrix off the stack and return the transform that is current
+// afterwards.
+func (s *TransformStack) Pop() Matrix {
+	if len(s.stack) > 0 {
+		s.stack = s.stack[:len(s.stack)-1]
+	}
+	return s.Current()
+}
+
+// Current returns the current transform.
+func (s *TransformStack) Current() Matrix {
+	if len(s.stack) == 0 {
+		return Identity
+	}
+	return s.stack[len(s.stack)-1]
+}
+
+// Len returns the number of matrices on the stack.
+func (s *TransformStack) Len() int {
+	return len(s.stack)
+}
+
+// quad returns the four corners of the rect after the matrix is applied, in
+// order around the rect.
+func (m Matrix) quad(r Rect) [4][2]float64 {
+	var (
+		x1 = float64(r.X)
+		y1 = float64(r.Y)
+		x2 = float64(r.X + r.W)
+		y2 = float64(r.Y + r.H)
+		q  [4][2]float64
+	)
+	q[0][0], q[0][1] = m.Apply(x1, y1)
+	q[1][0], q[1][1] = m.Apply(x2, y1)
+	q[2][0], q[2][1] = m.Apply(x2, y2)
+	q[3][0], q[3][1] = m.Apply(x1, y2)
+	return q
+}
+
+// round a float to the nearest integer.
+func round(v float64) int {
+	return int(math.Floor(v + 0.5))
+}
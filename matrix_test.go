@@ -0,0 +1,105 @@
+package render_test
+
+import (
+	"math"
+	"testing"
+
+	"git.kirsle.net/go/render"
+)
+
+func TestMatrix(t *testing.T) {
+	var tests = []struct {
+		Matrix render.Matrix
+		Point  render.Point
+		Expect render.Point
+	}{
+		{render.Identity, render.NewPoint(3, 4), render.NewPoint(3, 4)},
+		{render.Translation(10, -5), render.NewPoint(3, 4), render.NewPoint(13, -1)},
+		{render.Scaling(2, 3), render.NewPoint(3, 4), render.NewPoint(6, 12)},
+		{render.Rotation(math.Pi / 2), render.NewPoint(10, 0), render.NewPoint(0, 10)},
+
+		// Chained transforms apply the last one first.
+		{render.Translation(100, 0).Scale(2, 2), render.NewPoint(1, 1), render.NewPoint(102, 2)},
+		{render.Scaling(2, 2).Translate(100, 0), render.NewPoint(1, 1), render.NewPoint(202, 2)},
+	}
+	for _, test := range tests {
+		if actual := test.Matrix.TransformPoint(test.Point); actual != test.Expect {
+			t.Errorf("%s applied to %s: expected %s, got %s",
+				test.Matrix, test.Point, test.Expect, actual,
+			)
+		}
+
+		// The inverse brings the point back.
+		inverse, ok := test.Matrix.Invert()
+		if !ok {
+			t.Errorf("%s: expected it to be invertible", test.Matrix)
+			continue
+		}
+		if actual := inverse.TransformPoint(test.Expect); actual != test.Point {
+			t.Errorf("inverse of %s applied to %s: expected %s, got %s",
+				test.Matrix, test.Expect, test.Point, actual,
+			)
+		}
+	}
+
+	if _, ok := render.Scaling(0, 1).Invert(); ok {
+		t.Errorf("a zero scale should not be invertible")
+	}
+}
+
+func TestMatrixBoxes(t *testing.T) {
+	var collect = func(m render.Matrix, r render.Rect) []render.Rect {
+		var boxes []render.Rect
+		m.Boxes(r, func(box render.Rect) {
+			boxes = append(boxes, box)
+		})
+		return boxes
+	}
+
+	// A zoomed in pixel becomes a larger box.
+	boxes := collect(render.Translation(10, 10).Scale(4, 4), render.Rect{X: 1, Y: 1, W: 1, H: 1})
+	if len(boxes) != 1 || boxes[0] != (render.Rect{X: 14, Y: 14, W: 4, H: 4}) {
+		t.Errorf("zoomed pixel: expected a 4x4 box at 14,14, got %v", boxes)
+	}
+
+	// Zoomed out pixels don't vanish.
+	boxes = collect(render.Scaling(0.25, 0.25), render.Rect{X: 4, Y: 4, W: 1, H: 1})
+	if len(boxes) != 1 || boxes[0] != (render.Rect{X: 1, Y: 1, W: 1, H: 1}) {
+		t.Errorf("zoomed out pixel: expected a 1x1 box at 1,1, got %v", boxes)
+	}
+
+	// A rotated square covers about as many pixels as the original.
+	var area int
+	for _, box := range collect(render.Translation(50, 50).Rotate(math.Pi/4), render.Rect{X: -10, Y: -10, W: 20, H: 20}) {
+		if box.H != 1 {
+			t.Errorf("rotated rect: expected one row per box, got %s", box)
+		}
+		area += box.Area()
+	}
+	if area < 380 || area > 420 {
+		t.Errorf("rotated rect: expected an area of about 400 pixels, got %d", area)
+	}
+}
+
+func TestTransformStack(t *testing.T) {
+	var stack render.TransformStack
+
+	if !stack.Current().IsIdentity() {
+		t.Errorf("a new TransformStack should be the identity")
+	}
+
+	// Nested transforms happen in the local coordinates of the outer one.
+	stack.Push(render.Translation(10, 10))
+	stack.Push(render.Scaling(2, 2))
+	if actual := stack.Current().TransformPoint(render.NewPoint(5, 5)); actual != render.NewPoint(20, 20) {
+		t.Errorf("nested transforms: expected 20,20, got %s", actual)
+	}
+
+	if actual := stack.Pop(); actual != render.Translation(10, 10) {
+		t.Errorf("after Pop: expected the first transform, got %s", actual)
+	}
+	stack.Pop()
+	if !stack.Pop().IsIdentity() || stack.Len() != 0 {
+		t.Errorf("after popping everything: expected the identity")
+	}
+}
@@ -0,0 +1,669 @@
+package render
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// Path is a shape made of straight lines and curves, like the paths of SVG
+// and the HTML Canvas. Build one with MoveTo, LineTo, QuadTo, CubicTo, ArcTo
+// and Close, or parse it from SVG path data with ParsePath, then draw it on
+// any Engine with Fill and Stroke.
+//
+// Coordinates are the centers of pixels, like the points of PolygonSpans:
+// the path "M0,0 H10 V10 H0 Z" fills the same pixels as a 10x10 Rect at 0,0,
+// and stroking it covers the same pixels as DrawPolyline with those corners.
+//
+// The zero value is an empty path.
+type Path struct {
+	segments []pathSegment
+
+	// The start of the current subpath, and the current point.
+	start, current [2]float64
+	moved          bool
+}
+
+// pathOp is the kind of a pathSegment.
+type pathOp int
+
+const (
+	pathMove pathOp = iota
+	pathLine
+	pathQuad
+	pathCubic
+	pathClose
+)
+
+// pathSegment is one command of a Path. Its points are the control points
+// followed by the end point, as many as the op needs.
+type pathSegment struct {
+	op     pathOp
+	points [3][2]float64
+}
+
+// pathContour is a subpath flattened into straight lines.
+type pathContour struct {
+	points [][2]float64
+	closed bool
+}
+
+// ParsePath parses SVG path data, such as "M10,10 h20 v20 h-20 z".
+//
+// All of the SVG path commands are supported, in absolute (upper case) and
+// relative (lower case) forms: M, L, H, V, C, S, Q, T, A and Z.
+func ParsePath(data string) (*Path, error) {
+	var (
+		path   = &Path{}
+		parser = pathParser{data: data}
+		cmd    byte
+		prev   byte       // the previous command, for S and T
+		ctrl   [2]float64 // its last control point
+	)
+
+	for {
+		parser.skip()
+		if parser.done() {
+			break
+		}
+
+		// A command letter, or more numbers repeating the last command.
+		if c := parser.data[parser.pos]; isPathCommand(c) {
+			cmd = c
+			parser.pos++
+		} else if cmd == 0 {
+			return nil, parser.errorf("expected a command")
+		} else if cmd == 'Z' || cmd == 'z' {
+			return nil, parser.errorf("unexpected number after %c", cmd)
+		}
+
+		if !path.moved && cmd != 'M' && cmd != 'm' {
+			return nil, parser.errorf("path must start with a MoveTo")
+		}
+
+		var (
+			relative = cmd >= 'a'
+			origin   [2]float64
+			args     []float64
+			err      error
+		)
+		if relative {
+			origin = path.current
+		}
+
+		switch cmd {
+		case 'M', 'm':
+			if args, err = parser.numbers(2); err != nil {
+				return nil, err
+			}
+			path.MoveTo(origin[0]+args[0], origin[1]+args[1])
+
+			// Further coordinates are implicit LineTo commands.
+			if relative {
+				cmd = 'l'
+			} else {
+				cmd = 'L'
+			}
+		case 'L', 'l':
+			if args, err = parser.numbers(2); err != nil {
+				return nil, err
+			}
+			path.LineTo(origin[0]+args[0], origin[1]+args[1])
+		case 'H', 'h':
+			if args, err = parser.numbers(1); err != nil {
+				return nil, err
+			}
+			path.LineTo(origin[0]+args[0], path.current[1])
+		case 'V', 'v':
+			if args, err = parser.numbers(1); err != nil {
+				return nil, err
+			}
+			path.LineTo(path.current[0], origin[1]+args[0])
+		case 'C', 'c', 'S', 's':
+			var smooth = cmd == 'S' || cmd == 's'
+			if smooth {
+				args, err = parser.numbers(4)
+			} else {
+				args, err = parser.numbers(6)
+			}
+			if err != nil {
+				return nil, err
+			}
+
+			// The first control point of a smooth curve is the reflection
+			// of the last one of the previous curve.
+			if smooth {
+				var c1 = path.current
+				if prev == 'C' || prev == 'c' || prev == 'S' || prev == 's' {
+					c1 = [2]float64{2*path.current[0] - ctrl[0], 2*path.current[1] - ctrl[1]}
+				}
+				args = append([]float64{c1[0] - origin[0], c1[1] - origin[1]}, args...)
+			}
+
+			ctrl = [2]float64{origin[0] + args[2], origin[1] + args[3]}
+			path.CubicTo(
+				origin[0]+args[0], origin[1]+args[1],
+				ctrl[0], ctrl[1],
+				origin[0]+args[4], origin[1]+args[5],
+			)
+		case 'Q', 'q', 'T', 't':
+			var smooth = cmd == 'T' || cmd == 't'
+			if smooth {
+				args, err = parser.numbers(2)
+			} else {
+				args, err = parser.numbers(4)
+			}
+			if err != nil {
+				return nil, err
+			}
+
+			if smooth {
+				var c = path.current
+				if prev == 'Q' || prev == 'q' || prev == 'T' || prev == 't' {
+					c = [2]float64{2*path.current[0] - ctrl[0], 2*path.current[1] - ctrl[1]}
+				}
+				args = append([]float64{c[0] - origin[0], c[1] - origin[1]}, args...)
+			}
+
+			ctrl = [2]float64{origin[0] + args[0], origin[1] + args[1]}
+			path.QuadTo(ctrl[0], ctrl[1], origin[0]+args[2], origin[1]+args[3])
+		case 'A', 'a':
+			var (
+				radii    []float64
+				rotation []float64
+				large    bool
+				sweep    bool
+				end      []float64
+			)
+			if radii, err = parser.numbers(2); err != nil {
+				return nil, err
+			}
+			if rotation, err = parser.numbers(1); err != nil {
+				return nil, err
+			}
+			if large, err = parser.flag(); err != nil {
+				return nil, err
+			}
+			if sweep, err = parser.flag(); err != nil {
+				return nil, err
+			}
+			if end, err = parser.numbers(2); err != nil {
+				return nil, err
+			}
+			path.ArcTo(radii[0], radii[1], rotation[0]*math.Pi/180, large, sweep,
+				origin[0]+end[0], origin[1]+end[1],
+			)
+		case 'Z', 'z':
+			path.Close()
+		}
+
+		prev = cmd
+	}
+
+	return path, nil
+}
+
+// MoveTo starts a new subpath at X,Y.
+func (p *Path) MoveTo(x, y float64) {
+	p.segments = append(p.segments, pathSegment{
+		op:     pathMove,
+		points: [3][2]float64{{x, y}},
+	})
+	p.start = [2]float64{x, y}
+	p.current = p.start
+	p.moved = true
+}
+
+// LineTo adds a straight line from the current point to X,Y. Without a
+// current point, it moves to X,Y instead.
+func (p *Path) LineTo(x, y float64) {
+	if !p.moved {
+		p.MoveTo(x, y)
+		return
+	}
+	p.add(pathLine, [2]float64{x, y})
+}
+
+// QuadTo adds a quadratic Bezier curve from the current point to X,Y, bending
+// towards the control point CX,CY.
+func (p *Path) QuadTo(cx, cy, x, y float64) {
+	p.ensure(cx, cy)
+	p.add(pathQuad, [2]float64{cx, cy}, [2]float64{x, y})
+}
+
+// CubicTo adds a cubic Bezier curve from the current point to X,Y, with the
+// control points C1 and C2.
+func (p *Path) CubicTo(c1x, c1y, c2x, c2y, x, y float64) {
+	p.ensure(c1x, c1y)
+	p.add(pathCubic, [2]float64{c1x, c1y}, [2]float64{c2x, c2y}, [2]float64{x, y})
+}
+
+// ArcTo adds part of an ellipse from the current point to X,Y, like the SVG
+// arc command.
+//
+// The ellipse has radii RX and RY and is rotated by an angle in radians. Of
+// the ellipses that fit between the two points, largeArc picks the longer
+// way around, and sweep picks the one that is drawn clockwise on screen. If
+// the radii are too small to reach X,Y they are scaled up until they do.
+func (p *Path) ArcTo(rx, ry, rotation float64, largeArc, sweep bool, x, y float64) {
+	if !p.moved {
+		p.MoveTo(x, y)
+		return
+	}
+
+	var x1, y1 = p.current[0], p.current[1]
+	if x1 == x && y1 == y {
+		return
+	}
+	rx, ry = math.Abs(rx), math.Abs(ry)
+	if rx == 0 || ry == 0 {
+		p.LineTo(x, y)
+		return
+	}
+
+	// Find the center of the ellipse, following the SVG implementation
+	// notes on converting from endpoint to center parameterization.
+	var (
+		sin = math.Sin(rotation)
+		cos = math.Cos(rotation)
+		dx  = (x1 - x) / 2
+		dy  = (y1 - y) / 2
+		x1p = cos*dx + sin*dy
+		y1p = -sin*dx + cos*dy
+	)
+	if lambda := x1p*x1p/(rx*rx) + y1p*y1p/(ry*ry); lambda > 1 {
+		rx *= math.Sqrt(lambda)
+		ry *= math.Sqrt(lambda)
+	}
+
+	var (
+		num  = rx*rx*ry*ry - rx*rx*y1p*y1p - ry*ry*x1p*x1p
+		den  = rx*rx*y1p*y1p + ry*ry*x1p*x1p
+		coef = math.Sqrt(math.Max(0, num/den))
+	)
+	if largeArc == sweep {
+		coef = -coef
+	}
+	var (
+		cxp = coef * rx * y1p / ry
+		cyp = -coef * ry * x1p / rx
+		cx  = cos*cxp - sin*cyp + (x1+x)/2
+		cy  = sin*cxp + cos*cyp + (y1+y)/2
+
+		theta = math.Atan2((y1p-cyp)/ry, (x1p-cxp)/rx)
+		delta = math.Atan2((-y1p-cyp)/ry, (-x1p-cxp)/rx) - theta
+	)
+	if sweep && delta < 0 {
+		delta += 2 * math.Pi
+	} else if !sweep && delta > 0 {
+		delta -= 2 * math.Pi
+	}
+
+	// Draw it as cubic curves of up to a quarter turn each.
+	var (
+		n    = int(math.Ceil(math.Abs(delta)/(math.Pi/2) - 1e-9))
+		step = delta / float64(n)
+		k    = 4.0 / 3.0 * math.Tan(step/4)
+	)
+	// The point on the ellipse at angle t, and the tangent there.
+	point := func(t float64) (x, y, dx, dy float64) {
+		var (
+			ct = math.Cos(t)
+			st = math.Sin(t)
+		)
+		return cx + cos*rx*ct - sin*ry*st, cy + sin*rx*ct + cos*ry*st,
+			-cos*rx*st - sin*ry*ct, -sin*rx*st + cos*ry*ct
+	}
+	for i := 0; i < n; i++ {
+		var (
+			t1                 = theta + float64(i)*step
+			t2                 = t1 + step
+			ax, ay, adx, ady   = point(t1)
+			bx, by, bdx, bdy   = point(t2)
+			c1x, c1y, c2x, c2y = ax + k*adx, ay + k*ady, bx - k*bdx, by - k*bdy
+		)
+		if i == n-1 {
+			bx, by = x, y
+		}
+		p.CubicTo(c1x, c1y, c2x, c2y, bx, by)
+	}
+}
+
+// Close the current subpath with a straight line back to where it started.
+func (p *Path) Close() {
+	if !p.moved {
+		return
+	}
+	p.segments = append(p.segments, pathSegment{op: pathClose})
+	p.current = p.start
+}
+
+// IsEmpty returns whether nothing was added to the path.
+func (p *Path) IsEmpty() bool {
+	return len(p.segments) == 0
+}
+
+func (p *Path) String() string {
+	var parts = make([]string, 0, len(p.segments))
+	for _, seg := range p.segments {
+		var (
+			part   strings.Builder
+			points []string
+		)
+		switch seg.op {
+		case pathMove:
+			part.WriteString("M")
+			points = []string{pathCoord(seg.points[0])}
+		case pathLine:
+			part.WriteString("L")
+			points = []string{pathCoord(seg.points[0])}
+		case pathQuad:
+			part.WriteString("Q")
+			points = []string{pathCoord(seg.points[0]), pathCoord(seg.points[1])}
+		case pathCubic:
+			part.WriteString("C")
+			points = []string{pathCoord(seg.points[0]), pathCoord(seg.points[1]), pathCoord(seg.points[2])}
+		case pathClose:
+			part.WriteString("Z")
+		}
+		part.WriteString(strings.Join(points, " "))
+		parts = append(parts, part.String())
+	}
+	return strings.Join(parts, " ")
+}
+
+// MarshalText encodes the path as SVG path data, so paths can be stored in
+// JSON files. Arcs are written out as the cubic curves that draw them.
+func (p *Path) MarshalText() ([]byte, error) {
+	return []byte(p.String()), nil
+}
+
+// UnmarshalText parses SVG path data into the path.
+func (p *Path) UnmarshalText(b []byte) error {
+	path, err := ParsePath(string(b))
+	if err != nil {
+		return err
+	}
+	*p = *path
+	return nil
+}
+
+// Bounds returns the rect around every pixel that a point of the path falls
+// on, including the control points of curves. Thick strokes reach further
+// by half their width.
+func (p *Path) Bounds() Rect {
+	var (
+		minX, minY = math.Inf(1), math.Inf(1)
+		maxX, maxY = math.Inf(-1), math.Inf(-1)
+	)
+	for _, seg := range p.segments {
+		var n = 1
+		switch seg.op {
+		case pathClose:
+			continue
+		case pathQuad:
+			n = 2
+		case pathCubic:
+			n = 3
+		}
+		for _, pt := range seg.points[:n] {
+			minX = math.Min(minX, pt[0])
+			minY = math.Min(minY, pt[1])
+			maxX = math.Max(maxX, pt[0])
+			maxY = math.Max(maxY, pt[1])
+		}
+	}
+	if math.IsInf(minX, 0) {
+		return Rect{}
+	}
+
+	var (
+		x1 = round(minX)
+		y1 = round(minY)
+	)
+	return Rect{
+		X: x1,
+		Y: y1,
+		W: round(maxX) - x1 + 1,
+		H: round(maxY) - y1 + 1,
+	}
+}
+
+// Spans returns the rows of pixels inside the path when it's filled. Every
+// subpath is closed automatically, and subpaths inside of others cut holes
+// in them by the fill rule.
+func (p *Path) Spans(rule FillRule) []Span {
+	var (
+		contours = p.contours()
+		polygons = make([][][2]float64, len(contours))
+		spans    []Span
+	)
+	for i, contour := range contours {
+		polygons[i] = contour.points
+	}
+
+	fillPolygon(polygons, rule, func(y, x1, x2 int) {
+		spans = append(spans, Span{Y: y, X1: x1, X2: x2})
+	})
+	return spans
+}
+
+// StrokeSpans returns the rows of pixels covered by stroking the path. The
+// subpaths that were closed are joined all the way around. A subpath that is
+// only a MoveTo draws nothing, like in SVG.
+func (p *Path) StrokeSpans(style StrokeStyle) []Span {
+	var spans []Span
+	for _, contour := range p.contours() {
+		var points = contour.points
+		if len(points) < 2 {
+			continue
+		}
+		if contour.closed && points[0] != points[len(points)-1] {
+			points = append(points, points[0])
+		}
+		spans = style.spans(spans, points)
+	}
+	return mergeSpans(spans)
+}
+
+// Contains returns whether the pixel at a point is inside the filled path,
+// for hit-testing the mouse cursor against a shape.
+func (p *Path) Contains(pt Point, rule FillRule) bool {
+	var (
+		cx      = float64(pt.X) + 0.5
+		cy      = float64(pt.Y) + 0.5
+		winding int
+	)
+
+	// Count the edges that cross the pixel's row to the left of its center,
+	// the same as the scanlines of Spans.
+	for _, contour := range p.contours() {
+		for i, a := range contour.points {
+			var b = contour.points[(i+1)%len(contour.points)]
+			if a[1] <= cy && cy < b[1] {
+				if a[0]+(cy-a[1])*(b[0]-a[0])/(b[1]-a[1]) <= cx {
+					winding++
+				}
+			} else if b[1] <= cy && cy < a[1] {
+				if a[0]+(cy-a[1])*(b[0]-a[0])/(b[1]-a[1]) <= cx {
+					winding--
+				}
+			}
+		}
+	}
+	return inside(winding, rule)
+}
+
+// Fill the path with a color on an Engine.
+func (p *Path) Fill(e Engine, color Color, rule FillRule) {
+	for _, span := range p.Spans(rule) {
+		e.DrawBox(color, span.Rect())
+	}
+}
+
+// Stroke the outline of the path with a color on an Engine.
+func (p *Path) Stroke(e Engine, color Color, style StrokeStyle) {
+	for _, span := range p.StrokeSpans(style) {
+		e.DrawBox(color, span.Rect())
+	}
+}
+
+// contours flattens the path into straight lines, one contour per subpath,
+// with pixel centers at +0.5.
+func (p *Path) contours() []pathContour {
+	var (
+		contours []pathContour
+		current  *pathContour
+		start    [2]float64
+		last     [2]float64
+	)
+	add := func(pt [2]float64) bool {
+		current.points = append(current.points, [2]float64{pt[0] + 0.5, pt[1] + 0.5})
+		last = pt
+		return true
+	}
+	// begin a new contour; current always points at the newest one.
+	begin := func(pt [2]float64) {
+		contours = append(contours, pathContour{})
+		current = &contours[len(contours)-1]
+		add(pt)
+	}
+
+	for _, seg := range p.segments {
+		if seg.op == pathMove {
+			start = seg.points[0]
+			begin(start)
+			continue
+		}
+
+		// Drawing on after a Close starts a new subpath from its start.
+		if current == nil || current.closed {
+			begin(start)
+		}
+
+		switch seg.op {
+		case pathLine:
+			add(seg.points[0])
+		case pathQuad:
+			flattenQuad(last, seg.points[0], seg.points[1], bezierTolerance, add)
+		case pathCubic:
+			flattenCubic(last, seg.points[0], seg.points[1], seg.points[2], bezierTolerance, add)
+		case pathClose:
+			current.closed = true
+			last = start
+		}
+	}
+	return contours
+}
+
+// add appends a segment from the current point.
+func (p *Path) add(op pathOp, points ...[2]float64) {
+	var seg = pathSegment{op: op}
+	copy(seg.points[:], points)
+	p.segments = append(p.segments, seg)
+	p.current = points[len(points)-1]
+}
+
+// ensure there is a current point, moving to X,Y if there isn't.
+func (p *Path) ensure(x, y float64) {
+	if !p.moved {
+		p.MoveTo(x, y)
+	}
+}
+
+// pathCoord formats a coordinate for SVG path data.
+func pathCoord(pt [2]float64) string {
+	return strconv.FormatFloat(pt[0], 'g', -1, 64) + "," + strconv.FormatFloat(pt[1], 'g', -1, 64)
+}
+
+// pathParser reads the numbers and flags of SVG path data.
+type pathParser struct {
+	data string
+	pos  int
+}
+
+// isPathCommand returns whether a character is an SVG path command.
+func isPathCommand(c byte) bool {
+	return strings.IndexByte("MmLlHhVvCcSsQqTtAaZz", c) >= 0
+}
+
+// errorf returns a parse error at the current position.
+func (p *pathParser) errorf(format string, v ...interface{}) error {
+	return fmt.Errorf("ParsePath: %s at offset %d", fmt.Sprintf(format, v...), p.pos)
+}
+
+// done returns whether the whole string was read.
+func (p *pathParser) done() bool {
+	return p.pos >= len(p.data)
+}
+
+// skip whitespace and commas.
+func (p *pathParser) skip() {
+	for !p.done() && strings.IndexByte(" \t\r\n,", p.data[p.pos]) >= 0 {
+		p.pos++
+	}
+}
+
+// numbers reads n numbers.
+func (p *pathParser) numbers(n int) ([]float64, error) {
+	var result = make([]float64, n)
+	for i := range result {
+		p.skip()
+		var start = p.pos
+
+		// An optional sign, digits with an optional decimal point, and an
+		// optional exponent. Numbers can run together, as in "1.5.5" or
+		// "10-5", so stop where the next one starts.
+		if !p.done() && (p.data[p.pos] == '+' || p.data[p.pos] == '-') {
+			p.pos++
+		}
+		var digits = p.digits()
+		if !p.done() && p.data[p.pos] == '.' {
+			p.pos++
+			digits += p.digits()
+		}
+		if digits == 0 {
+			p.pos = start
+			return nil, p.errorf("expected a number")
+		}
+		if !p.done() && (p.data[p.pos] == 'e' || p.data[p.pos] == 'E') {
+			var mark = p.pos
+			p.pos++
+			if !p.done() && (p.data[p.pos] == '+' || p.data[p.pos] == '-') {
+				p.pos++
+			}
+			if p.digits() == 0 {
+				p.pos = mark
+			}
+		}
+
+		var token = p.data[start:p.pos]
+		v, err := strconv.ParseFloat(token, 64)
+		if err != nil {
+			p.pos = start
+			return nil, p.errorf("bad number %q", token)
+		}
+		result[i] = v
+	}
+	return result, nil
+}
+
+// digits skips over digits and returns how many there were.
+func (p *pathParser) digits() int {
+	var start = p.pos
+	for !p.done() && p.data[p.pos] >= '0' && p.data[p.pos] <= '9' {
+		p.pos++
+	}
+	return p.pos - start
+}
+
+// flag reads an arc flag, a 0 or 1 that needs no separator after it.
+func (p *pathParser) flag() (bool, error) {
+	p.skip()
+	if p.done() || (p.data[p.pos] != '0' && p.data[p.pos] != '1') {
+		return false, p.errorf("expected a flag")
+	}
+	p.pos++
+	return p.data[p.pos-1] == '1', nil
+}
@@ -0,0 +1,207 @@
+package render_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"git.kirsle.net/go/render"
+	"git.kirsle.net/go/render/software"
+)
+
+func TestParsePath(t *testing.T) {
+	var tests = []struct {
+		Data   string
+		Expect string
+	}{
+		{"M10,10 h20 v20 h-20 z", "M10,10 L30,10 L30,30 L10,30 Z"},
+		{"M0 0 10 0 10 10", "M0,0 L10,0 L10,10"},                     // implicit LineTo
+		{"m5 5 10 0 0 10z m1 1 h2", "M5,5 L15,5 L15,15 Z M6,6 L8,6"}, // relative to the subpath start
+		{"M0-1.5.5-2e1", "M0,-1.5 L0.5,-20"},                         // numbers running together
+		{"M0 0 Q10 0 10 10 T20 20", "M0,0 Q10,0 10,10 Q10,20 20,20"},
+		{"M0 0 C0 10 10 10 10 0 S20 -10 20 0", "M0,0 C0,10 10,10 10,0 C10,-10 20,-10 20,0"},
+		{"M0 0 s5 5 10 0", "M0,0 C0,0 5,5 10,0"}, // no curve to reflect
+		{"M0 0 A0 5 0 0 1 10 0", "M0,0 L10,0"},   // zero radius arcs are lines
+		{"", ""},
+	}
+	for _, test := range tests {
+		path, err := render.ParsePath(test.Data)
+		if err != nil {
+			t.Errorf("ParsePath(%q): unexpected error: %s", test.Data, err)
+			continue
+		}
+		if actual := path.String(); actual != test.Expect {
+			t.Errorf("ParsePath(%q): expected %q, got %q", test.Data, test.Expect, actual)
+		}
+	}
+
+	for _, data := range []string{
+		"L10 10",       // doesn't start with a MoveTo
+		"10 10",        // no command
+		"M10",          // missing a coordinate
+		"M0 0 X10",     // unknown command
+		"M0 0 Z 10 10", // numbers after a close
+		"M0 0 A5 5 0 2 1 10 0",
+	} {
+		if _, err := render.ParsePath(data); err == nil {
+			t.Errorf("ParsePath(%q): expected an error", data)
+		}
+	}
+
+	// A number out of range is reported with its text and offset.
+	var expect = `ParsePath: bad number "1e999" at offset 5`
+	if _, err := render.ParsePath("M0 0 1e999 10"); err == nil || err.Error() != expect {
+		t.Errorf("out of range number: expected error %q, got %v", expect, err)
+	}
+
+	// Compact arc flags.
+	path, err := render.ParsePath("M0 10 a10 10 0 0120 0")
+	if err != nil {
+		t.Fatalf("ParsePath arc: %s", err)
+	}
+	if actual := path.Bounds(); actual != (render.Rect{X: 0, Y: 0, W: 21, H: 11}) {
+		t.Errorf("arc bounds: expected a half circle above the line, got %s", actual)
+	}
+}
+
+func TestPathFill(t *testing.T) {
+	var tests = []struct {
+		Name   string
+		Data   string
+		Rule   render.FillRule
+		Inside []render.Point
+		Out    []render.Point
+	}{
+		{
+			Name:   "square",
+			Data:   "M0,0 H10 V10 H0 Z",
+			Inside: []render.Point{{X: 0, Y: 0}, {X: 9, Y: 9}},
+			Out:    []render.Point{{X: 10, Y: 5}, {X: 5, Y: 10}, {X: -1, Y: 0}},
+		},
+		{
+			Name:   "hole by winding",
+			Data:   "M0,0 H10 V10 H0 Z M3,3 V7 H7 V3 Z",
+			Inside: []render.Point{{X: 1, Y: 1}, {X: 8, Y: 5}},
+			Out:    []render.Point{{X: 5, Y: 5}},
+		},
+		{
+			Name:   "overlap filled by nonzero",
+			Data:   "M0,0 H10 V10 H0 Z M3,3 H7 V7 H3 Z",
+			Inside: []render.Point{{X: 5, Y: 5}},
+		},
+		{
+			Name:   "overlap cut by even-odd",
+			Data:   "M0,0 H10 V10 H0 Z M3,3 H7 V7 H3 Z",
+			Rule:   render.FillEvenOdd,
+			Inside: []render.Point{{X: 1, Y: 1}},
+			Out:    []render.Point{{X: 5, Y: 5}},
+		},
+		{
+			Name:   "circle",
+			Data:   "M0,10 A10,10 0 0 1 20,10 A10,10 0 0 1 0,10 Z",
+			Inside: []render.Point{{X: 10, Y: 10}, {X: 1, Y: 9}, {X: 18, Y: 10}},
+			Out:    []render.Point{{X: 1, Y: 1}, {X: 18, Y: 18}, {X: 10, Y: 20}},
+		},
+	}
+	for _, test := range tests {
+		path, err := render.ParsePath(test.Data)
+		if err != nil {
+			t.Fatalf("%s: %s", test.Name, err)
+		}
+
+		// Contains agrees with the pixels that are filled.
+		var filled = map[render.Point]bool{}
+		for _, span := range path.Spans(test.Rule) {
+			for x := span.X1; x <= span.X2; x++ {
+				filled[render.NewPoint(x, span.Y)] = true
+			}
+		}
+		for y := -2; y < 22; y++ {
+			for x := -2; x < 22; x++ {
+				var pt = render.NewPoint(x, y)
+				if path.Contains(pt, test.Rule) != filled[pt] {
+					t.Errorf("%s: Contains(%s) is %v, but the fill has %v",
+						test.Name, pt, !filled[pt], filled[pt],
+					)
+				}
+			}
+		}
+
+		for _, pt := range test.Inside {
+			if !path.Contains(pt, test.Rule) {
+				t.Errorf("%s: expected %s to be inside", test.Name, pt)
+			}
+		}
+		for _, pt := range test.Out {
+			if path.Contains(pt, test.Rule) {
+				t.Errorf("%s: expected %s to be outside", test.Name, pt)
+			}
+		}
+	}
+}
+
+func TestPathStroke(t *testing.T) {
+	// A closed path strokes the same pixels as a closed polyline.
+	var (
+		path, _ = render.ParsePath("M0,0 H10 V10 H0 Z")
+		corners = []render.Point{{X: 0, Y: 0}, {X: 10, Y: 0}, {X: 10, Y: 10}, {X: 0, Y: 10}, {X: 0, Y: 0}}
+	)
+	if expect, actual := spanPixels(t, render.StrokeStyle{Join: render.JoinBevel}.Spans(corners)),
+		spanPixels(t, path.StrokeSpans(render.StrokeStyle{Join: render.JoinBevel})); len(expect) != len(actual) {
+		t.Errorf("closed stroke: expected %d pixels, got %d", len(expect), len(actual))
+	} else {
+		for pt := range expect {
+			if !actual[pt] {
+				t.Errorf("closed stroke: missing pixel %s", pt)
+			}
+		}
+	}
+
+	// A lone MoveTo draws nothing, even with round caps.
+	var round = render.StrokeStyle{Width: 3, Cap: render.CapRound}
+	for _, data := range []string{"M5 5", "M5 5 Z", "M0 0 L4 0 M10 10"} {
+		var (
+			p, _   = render.ParsePath(data)
+			pixels = spanPixels(t, p.StrokeSpans(round))
+		)
+		if pixels[render.NewPoint(5, 5)] || pixels[render.NewPoint(10, 10)] {
+			t.Errorf("%q: expected no dot at the MoveTo", data)
+		}
+	}
+
+	// Drawn on an engine.
+	e := software.New(16, 16)
+	e.Clear(render.White)
+	path.Fill(e, render.Blue, render.FillNonZero)
+	path.Stroke(e, render.Red, render.StrokeStyle{})
+	for _, test := range []struct {
+		X, Y   int
+		Expect render.Color
+	}{
+		{0, 0, render.Red},
+		{10, 10, render.Red},
+		{10, 5, render.Red},
+		{5, 5, render.Blue},
+		{11, 5, render.White},
+	} {
+		if actual := render.FromColor(e.Image().At(test.X, test.Y)); actual != test.Expect {
+			t.Errorf("pixel at %d,%d: expected %s, got %s", test.X, test.Y, test.Expect, actual)
+		}
+	}
+}
+
+func TestPathJSON(t *testing.T) {
+	var shape struct {
+		Outline *render.Path `json:"outline"`
+	}
+	if err := json.Unmarshal([]byte(`{"outline": "M0 0 l5 5"}`), &shape); err != nil {
+		t.Fatalf("json.Unmarshal: %s", err)
+	}
+
+	b, err := json.Marshal(shape)
+	if err != nil {
+		t.Fatalf("json.Marshal: %s", err)
+	}
+	if expect := `{"outline":"M0,0 L5,5"}`; string(b) != expect {
+		t.Errorf("expected %s, got %s", expect, b)
+	}
+}
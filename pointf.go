@@ -0,0 +1,179 @@
+package render
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+
+	"git.kirsle.net/go/render/event"
+)
+
+// PointF holds a floating point X,Y coordinate, for positions and velocities
+// that move by less than a pixel at a time. It doubles as a 2D vector.
+type PointF struct {
+	X float64
+	Y float64
+}
+
+// NewPointF makes a new PointF at an X,Y coordinate.
+func NewPointF(x, y float64) PointF {
+	return PointF{
+		X: x,
+		Y: y,
+	}
+}
+
+// FromVector converts an event.Vector, such as the position of a game
+// controller's stick, to a PointF.
+func FromVector(v event.Vector) PointF {
+	return PointF(v)
+}
+
+func (p PointF) String() string {
+	return strconv.FormatFloat(p.X, 'g', -1, 64) + "," + strconv.FormatFloat(p.Y, 'g', -1, 64)
+}
+
+// ParsePointF parses a point from its string representation.
+func ParsePointF(v string) (PointF, error) {
+	halves := strings.Split(v, ",")
+	if len(halves) != 2 {
+		return PointF{}, fmt.Errorf("'%s': not a valid coordinate string", v)
+	}
+	x, errX := strconv.ParseFloat(strings.TrimSpace(halves[0]), 64)
+	y, errY := strconv.ParseFloat(strings.TrimSpace(halves[1]), 64)
+	if errX != nil || errY != nil {
+		return PointF{}, fmt.Errorf("invalid coordinate string (X: %v; Y: %v)",
+			errX,
+			errY,
+		)
+	}
+	return PointF{
+		X: x,
+		Y: y,
+	}, nil
+}
+
+// PointF converts the point to floating point, without any loss.
+func (p Point) PointF() PointF {
+	return PointF{
+		X: float64(p.X),
+		Y: float64(p.Y),
+	}
+}
+
+// Point rounds the point to the nearest whole pixel. A PointF made from a
+// Point converts back to the same Point.
+func (p PointF) Point() Point {
+	return Point{
+		X: round(p.X),
+		Y: round(p.Y),
+	}
+}
+
+// Vector converts the point to an event.Vector, without any loss.
+func (p PointF) Vector() event.Vector {
+	return event.Vector(p)
+}
+
+// IsZero returns if the point is the zero value.
+func (p PointF) IsZero() bool {
+	return p.X == 0 && p.Y == 0
+}
+
+// Inside returns whether the point falls inside the rect. The left and top
+// edges of the rect are inside it, the right and bottom edges are not.
+func (p PointF) Inside(r RectF) bool {
+	r = r.Normalize()
+	return p.X >= r.X && p.X < r.X+r.W &&
+		p.Y >= r.Y && p.Y < r.Y+r.H
+}
+
+// Add returns the sum of the two points.
+func (p PointF) Add(other PointF) PointF {
+	return PointF{
+		X: p.X + other.X,
+		Y: p.Y + other.Y,
+	}
+}
+
+// Sub returns the other point subtracted from this one: the vector from the
+// other point to this one.
+func (p PointF) Sub(other PointF) PointF {
+	return PointF{
+		X: p.X - other.X,
+		Y: p.Y - other.Y,
+	}
+}
+
+// Scale returns the point multiplied by a factor.
+func (p PointF) Scale(factor float64) PointF {
+	return PointF{
+		X: p.X * factor,
+		Y: p.Y * factor,
+	}
+}
+
+// Dot returns the dot product of the two vectors.
+func (p PointF) Dot(other PointF) float64 {
+	return p.X*other.X + p.Y*other.Y
+}
+
+// Length returns the length of the vector.
+func (p PointF) Length() float64 {
+	return math.Hypot(p.X, p.Y)
+}
+
+// Normalize returns the vector scaled to a length of 1, pointing the same
+// way. The zero vector stays zero.
+func (p PointF) Normalize() PointF {
+	var length = p.Length()
+	if length == 0 {
+		return PointF{}
+	}
+	return p.Scale(1 / length)
+}
+
+// Lerp linearly interpolates towards the other point: it returns this point
+// at t=0, the other one at t=1, and points along the line between them in
+// between.
+func (p PointF) Lerp(other PointF, t float64) PointF {
+	return PointF{
+		X: p.X + (other.X-p.X)*t,
+		Y: p.Y + (other.Y-p.Y)*t,
+	}
+}
+
+// Rotate returns the vector rotated around the origin by an angle in
+// radians. Like Rotation, positive angles rotate clockwise on screen.
+func (p PointF) Rotate(radians float64) PointF {
+	var (
+		sin = math.Sin(radians)
+		cos = math.Cos(radians)
+	)
+	return PointF{
+		X: p.X*cos - p.Y*sin,
+		Y: p.X*sin + p.Y*cos,
+	}
+}
+
+// Distance returns how far apart the two points are.
+func (p PointF) Distance(other PointF) float64 {
+	return math.Hypot(other.X-p.X, other.Y-p.Y)
+}
+
+// MarshalText to convert the point into text so that a render.PointF may be
+// used as a map key and serialized to JSON.
+func (p PointF) MarshalText() ([]byte, error) {
+	return []byte(p.String()), nil
+}
+
+// UnmarshalText to restore it from text.
+func (p *PointF) UnmarshalText(b []byte) error {
+	point, err := ParsePointF(strings.Trim(string(b), `"`))
+	if err != nil {
+		return err
+	}
+	*p = point
+	return nil
+}
@@ -0,0 +1,91 @@
+package render_test
+
+import (
+	"encoding/json"
+	"math"
+	"testing"
+
+	"git.kirsle.net/go/render"
+	"git.kirsle.net/go/render/event"
+)
+
+func TestPointFMath(t *testing.T) {
+	var (
+		a = render.NewPointF(3, 4)
+		b = render.NewPointF(1, -2)
+	)
+
+	var points = []struct {
+		Name   string
+		Actual render.PointF
+		Expect render.PointF
+	}{
+		{"Add", a.Add(b), render.NewPointF(4, 2)},
+		{"Sub", a.Sub(b), render.NewPointF(2, 6)},
+		{"Scale", a.Scale(0.5), render.NewPointF(1.5, 2)},
+		{"Normalize", a.Normalize(), render.NewPointF(0.6, 0.8)},
+		{"Normalize zero", render.PointF{}.Normalize(), render.PointF{}},
+		{"Lerp", a.Lerp(b, 0.25), render.NewPointF(2.5, 2.5)},
+		{"Rotate", render.NewPointF(1, 0).Rotate(math.Pi / 2), render.NewPointF(0, 1)},
+	}
+	for _, test := range points {
+		if math.Abs(test.Actual.X-test.Expect.X) > 1e-9 || math.Abs(test.Actual.Y-test.Expect.Y) > 1e-9 {
+			t.Errorf("%s: expected %s, got %s", test.Name, test.Expect, test.Actual)
+		}
+	}
+
+	var scalars = []struct {
+		Name   string
+		Actual float64
+		Expect float64
+	}{
+		{"Dot", a.Dot(b), -5},
+		{"Length", a.Length(), 5},
+		{"Distance", a.Distance(b), math.Sqrt(40)},
+	}
+	for _, test := range scalars {
+		if math.Abs(test.Actual-test.Expect) > 1e-9 {
+			t.Errorf("%s: expected %g, got %g", test.Name, test.Expect, test.Actual)
+		}
+	}
+}
+
+func TestPointFConvert(t *testing.T) {
+	for _, p := range []render.Point{{X: 0, Y: 0}, {X: -7, Y: 12}, {X: 1 << 40, Y: -(1 << 40)}} {
+		if actual := p.PointF().Point(); actual != p {
+			t.Errorf("%s: expected to convert back to itself, got %s", p, actual)
+		}
+	}
+	if actual := render.NewPointF(1.5, -1.5).Point(); actual != render.NewPoint(2, -1) {
+		t.Errorf("Point: expected to round to 2,-1, got %s", actual)
+	}
+
+	var v = event.Vector{X: 0.25, Y: -1}
+	if actual := render.FromVector(v).Vector(); actual != v {
+		t.Errorf("Vector: expected %v, got %v", v, actual)
+	}
+
+	// Text round trip, also as a map key.
+	var points = map[render.PointF]string{
+		render.NewPointF(0.5, -2): "a",
+	}
+	b, err := json.Marshal(points)
+	if err != nil {
+		t.Fatalf("json.Marshal: %s", err)
+	}
+	if expect := `{"0.5,-2":"a"}`; string(b) != expect {
+		t.Errorf("json.Marshal: expected %s, got %s", expect, b)
+	}
+
+	var loaded map[render.PointF]string
+	if err := json.Unmarshal(b, &loaded); err != nil {
+		t.Fatalf("json.Unmarshal: %s", err)
+	}
+	if loaded[render.NewPointF(0.5, -2)] != "a" {
+		t.Errorf("json.Unmarshal: expected the point back, got %v", loaded)
+	}
+
+	if _, err := render.ParsePointF("1,x"); err == nil {
+		t.Errorf("ParsePointF: expected an error")
+	}
+}
@@ -0,0 +1,164 @@
+package render
+
+import (
+	"math"
+	"sort"
+)
+
+// FillRule decides which parts of a self-intersecting shape are inside it.
+type FillRule int
+
+// FillRule values.
+const (
+	// FillNonZero fills every area the outline winds around, like the
+	// default of the HTML Canvas and SVG.
+	FillNonZero FillRule = iota
+
+	// FillEvenOdd fills areas that are inside an odd number of outlines,
+	// leaving holes where shapes overlap.
+	FillEvenOdd
+)
+
+// PolygonSpans returns the rows of pixels inside a polygon, for any shape of
+// polygon including concave and self-intersecting ones. The polygon is
+// closed automatically from its last point back to the first.
+//
+// The points are the centers of pixels, and a pixel is inside when its center
+// is. The polygon with corners 0,0 and 10,10 covers the same pixels as a Rect
+// at 0,0 of size 10x10.
+func PolygonSpans(points []Point, rule FillRule) []Span {
+	var (
+		contour = make([][2]float64, len(points))
+		spans   []Span
+	)
+	for i, pt := range points {
+		contour[i] = [2]float64{float64(pt.X) + 0.5, float64(pt.Y) + 0.5}
+	}
+
+	fillPolygon([][][2]float64{contour}, rule, func(y, x1, x2 int) {
+		spans = append(spans, Span{Y: y, X1: x1, X2: x2})
+	})
+	return spans
+}
+
+// PolygonOutline returns the rows of pixels that outline a polygon one pixel
+// wide, as drawn by Engine.DrawPolygon.
+//
+// The outline is the edge of the pixels that PolygonSpans fills with the
+// even-odd rule, so it lies inside the filled polygon the same way DrawRect
+// lies inside DrawBox, and a self-intersecting polygon is outlined where it
+// crosses itself too. A polygon with no area, like a line, is drawn as a one
+// pixel wide stroke instead.
+func PolygonOutline(points []Point) []Span {
+	var fill = mergeSpans(PolygonSpans(points, FillEvenOdd))
+	if len(fill) == 0 {
+		if len(points) > 2 && points[0] != points[len(points)-1] {
+			points = append(points[:len(points):len(points)], points[0])
+		}
+		return StrokeStyle{Join: JoinBevel}.Spans(points)
+	}
+
+	var rows = map[int][]Span{}
+	for _, span := range fill {
+		rows[span.Y] = append(rows[span.Y], span)
+	}
+
+	// Pixels that are filled on all four sides are inside; the rest of the
+	// fill is its edge.
+	var outline []Span
+	for _, span := range fill {
+		var inside = intersectSpans(
+			intersectSpans([]Span{{Y: span.Y, X1: span.X1 + 1, X2: span.X2 - 1}}, rows[span.Y-1]),
+			rows[span.Y+1],
+		)
+		outline = append(outline, subtractSpans(span, inside)...)
+	}
+	return outline
+}
+
+// crossing is where an edge of a polygon crosses a scanline.
+type crossing struct {
+	x       float64
+	winding int
+}
+
+// fillPolygon scans one or more closed contours and calls fn with each
+// horizontal run of pixels whose centers are inside them, from x1 to x2
+// inclusive, in order from top to bottom.
+func fillPolygon(contours [][][2]float64, rule FillRule, fn func(y, x1, x2 int)) {
+	var (
+		minY      = math.Inf(1)
+		maxY      = math.Inf(-1)
+		crossings []crossing
+	)
+	for _, contour := range contours {
+		for _, pt := range contour {
+			minY = math.Min(minY, pt[1])
+			maxY = math.Max(maxY, pt[1])
+		}
+	}
+	if math.IsInf(minY, 0) {
+		return
+	}
+
+	for y := int(math.Floor(minY)); float64(y) < maxY; y++ {
+		var cy = float64(y) + 0.5
+
+		// Find where the scanline crosses the edges, and which way.
+		crossings = crossings[:0]
+		for _, contour := range contours {
+			for i := range contour {
+				var (
+					a = contour[i]
+					b = contour[(i+1)%len(contour)]
+				)
+				if a[1] <= cy && cy < b[1] {
+					crossings = append(crossings, crossing{
+						x:       a[0] + (cy-a[1])*(b[0]-a[0])/(b[1]-a[1]),
+						winding: 1,
+					})
+				} else if b[1] <= cy && cy < a[1] {
+					crossings = append(crossings, crossing{
+						x:       a[0] + (cy-a[1])*(b[0]-a[0])/(b[1]-a[1]),
+						winding: -1,
+					})
+				}
+			}
+		}
+		sort.Slice(crossings, func(i, j int) bool {
+			return crossings[i].x < crossings[j].x
+		})
+
+		// Walk along the row, filling between the crossings that enter and
+		// leave the inside.
+		var (
+			winding int
+			start   float64
+		)
+		for _, c := range crossings {
+			var before = inside(winding, rule)
+			winding += c.winding
+			var after = inside(winding, rule)
+
+			if !before && after {
+				start = c.x
+			} else if before && !after {
+				var (
+					x1 = int(math.Ceil(start - 0.5))
+					x2 = int(math.Ceil(c.x-0.5)) - 1
+				)
+				if x2 >= x1 {
+					fn(y, x1, x2)
+				}
+			}
+		}
+	}
+}
+
+// inside returns whether a winding number is inside the shape.
+func inside(winding int, rule FillRule) bool {
+	if rule == FillEvenOdd {
+		return winding%2 != 0
+	}
+	return winding != 0
+}
@@ -0,0 +1,95 @@
+package render_test
+
+import (
+	"testing"
+
+	"git.kirsle.net/go/render"
+)
+
+func TestPolygonSpans(t *testing.T) {
+	// A square polygon covers the same pixels as the Rect.
+	var square = []render.Point{{X: 2, Y: 3}, {X: 12, Y: 3}, {X: 12, Y: 8}, {X: 2, Y: 8}}
+	var pixels = spanPixels(t, render.PolygonSpans(square, render.FillNonZero))
+	if len(pixels) != 50 {
+		t.Errorf("square: expected 50 pixels, got %d", len(pixels))
+	}
+	for _, pt := range []render.Point{{X: 2, Y: 3}, {X: 11, Y: 7}} {
+		if !pixels[pt] {
+			t.Errorf("square: expected %s to be covered", pt)
+		}
+	}
+
+	// A concave U shape leaves its notch empty.
+	var u = []render.Point{
+		{X: 0, Y: 0}, {X: 4, Y: 0}, {X: 4, Y: 10}, {X: 8, Y: 10},
+		{X: 8, Y: 0}, {X: 12, Y: 0}, {X: 12, Y: 14}, {X: 0, Y: 14},
+	}
+	pixels = spanPixels(t, render.PolygonSpans(u, render.FillNonZero))
+	if pixels[render.NewPoint(6, 5)] || !pixels[render.NewPoint(2, 5)] || !pixels[render.NewPoint(6, 12)] {
+		t.Errorf("concave polygon: expected the notch to be empty")
+	}
+
+	// A five pointed star crosses itself: the middle is inside with the
+	// non-zero rule and a hole with the even-odd rule.
+	var (
+		star   = []render.Point{{X: 50, Y: 0}, {X: 79, Y: 90}, {X: 2, Y: 35}, {X: 98, Y: 35}, {X: 21, Y: 90}}
+		center = render.NewPoint(50, 50)
+		tip    = render.NewPoint(50, 10)
+	)
+	var tests = []struct {
+		Rule   render.FillRule
+		Center bool
+	}{
+		{render.FillNonZero, true},
+		{render.FillEvenOdd, false},
+	}
+	for _, test := range tests {
+		pixels := spanPixels(t, render.PolygonSpans(star, test.Rule))
+		if pixels[center] != test.Center {
+			t.Errorf("star with rule %d: center covered=%t, expected %t", test.Rule, pixels[center], test.Center)
+		}
+		if !pixels[tip] {
+			t.Errorf("star with rule %d: expected the tip to be covered", test.Rule)
+		}
+	}
+
+	// The outline traces the edge of the filled pixels, like DrawRect does
+	// for DrawBox.
+	var (
+		fill    = spanPixels(t, render.PolygonSpans(square, render.FillNonZero))
+		outline = spanPixels(t, render.PolygonOutline(square))
+		expect  = collect(render.RectPoints(render.NewPoint(2, 3), render.NewPoint(11, 7)))
+	)
+	for _, pt := range expect {
+		if !outline[pt] {
+			t.Errorf("outline: expected %s to be covered", pt)
+		}
+	}
+	if len(outline) != len(expect) {
+		t.Errorf("outline: expected %d pixels, got %d", len(expect), len(outline))
+	}
+	for pt := range outline {
+		if !fill[pt] {
+			t.Errorf("outline: %s is outside of the filled polygon", pt)
+		}
+	}
+
+	// The outline of the star's points is inside its fill too, and it
+	// follows the edges where it crosses itself.
+	fill = spanPixels(t, render.PolygonSpans(star, render.FillNonZero))
+	outline = spanPixels(t, render.PolygonOutline(star))
+	for pt := range outline {
+		if !fill[pt] {
+			t.Errorf("star outline: %s is outside of the filled polygon", pt)
+		}
+	}
+	if outline[center] || !outline[render.NewPoint(50, 34)] {
+		t.Errorf("star outline: expected the edge of the middle to be outlined and not its center")
+	}
+
+	// A polygon with no area is drawn as a line.
+	outline = spanPixels(t, render.PolygonOutline([]render.Point{{X: 0, Y: 0}, {X: 5, Y: 0}}))
+	if len(outline) != 6 {
+		t.Errorf("flat polygon: expected a line of 6 pixels, got %d", len(outline))
+	}
+}
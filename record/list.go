@@ -0,0 +1,98 @@
+package record
+
+import (
+	"encoding/json"
+	"io"
+
+	"git.kirsle.net/go/render"
+)
+
+// Op names a render.Engine function that was recorded.
+type Op string
+
+// Op values.
+const (
+	OpClear           Op = "Clear"
+	OpSetTitle        Op = "SetTitle"
+	OpDrawPoint       Op = "DrawPoint"
+	OpDrawLine        Op = "DrawLine"
+	OpDrawRect        Op = "DrawRect"
+	OpDrawBox         Op = "DrawBox"
+	OpDrawLineAA      Op = "DrawLineAA"
+	OpDrawPolyline    Op = "DrawPolyline"
+	OpDrawPolygon     Op = "DrawPolygon"
+	OpFillPolygon     Op = "FillPolygon"
+	OpDrawEllipse     Op = "DrawEllipse"
+	OpFillEllipse     Op = "FillEllipse"
+	OpDrawCircle      Op = "DrawCircle"
+	OpDrawArc         Op = "DrawArc"
+	OpDrawQuadBezier  Op = "DrawQuadBezier"
+	OpDrawCubicBezier Op = "DrawCubicBezier"
+	OpDrawText        Op = "DrawText"
+	OpPushClip        Op = "PushClip"
+	OpPopClip         Op = "PopClip"
+	OpPushTransform   Op = "PushTransform"
+	OpPopTransform    Op = "PopTransform"
+	OpSetBlendMode    Op = "SetBlendMode"
+	OpSetOpacity      Op = "SetOpacity"
+	OpStoreTexture    Op = "StoreTexture"
+	OpCopy            Op = "Copy"
+	OpFreeTextures    Op = "FreeTextures"
+	OpPresent         Op = "Present"
+)
+
+// Command is a single recorded call to the render.Engine. Only the fields
+// relevant to the Op are set.
+type Command struct {
+	Op      Op                  `json:"op"`
+	Color   *render.Color       `json:"color,omitempty"`
+	Points  []render.Point      `json:"points,omitempty"`
+	Coords  []float64           `json:"coords,omitempty"` // fractional coordinates, as X,Y pairs
+	Rect    *render.Rect        `json:"rect,omitempty"`
+	Src     *render.Rect        `json:"src,omitempty"` // source rect for Copy; Rect is the destination
+	Matrix  *render.Matrix      `json:"matrix,omitempty"`
+	Text    *render.Text        `json:"text,omitempty"`
+	Stroke  *render.StrokeStyle `json:"stroke,omitempty"`
+	Rule    render.FillRule     `json:"rule,omitempty"`
+	Blend   render.BlendMode    `json:"blend,omitempty"`
+	Title   string              `json:"title,omitempty"`
+	Texture string              `json:"texture,omitempty"` // texture name
+	Image   []byte              `json:"image,omitempty"`   // PNG encoded texture
+}
+
+// Frame is the list of commands drawn up to and including a call to Present.
+type Frame []Command
+
+// DisplayList holds the recorded frames.
+//
+// The last frame may not end with a Present command if the program hadn't
+// presented it yet when the list was taken from the Recorder.
+type DisplayList struct {
+	Frames []Frame `json:"frames"`
+}
+
+// Load a DisplayList from its JSON encoding.
+func Load(r io.Reader) (*DisplayList, error) {
+	var list = &DisplayList{}
+	if err := json.NewDecoder(r).Decode(list); err != nil {
+		return nil, err
+	}
+	return list, nil
+}
+
+// Save the DisplayList as indented JSON, which is friendly to diff between
+// two recordings.
+func (l *DisplayList) Save(w io.Writer) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "\t")
+	return enc.Encode(l)
+}
+
+// Len returns the number of commands in all of the frames.
+func (l *DisplayList) Len() int {
+	var n int
+	for _, frame := range l.Frames {
+		n += len(frame)
+	}
+	return n
+}
@@ -0,0 +1,325 @@
+// Package record provides a render.Engine that records every drawing call
+// into a DisplayList, which can be saved as JSON and replayed against any
+// other render.Engine.
+package record
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/png"
+
+	"git.kirsle.net/go/render"
+	"git.kirsle.net/go/render/event"
+)
+
+// Recorder wraps a render.Engine, passing every call through to it while
+// keeping a record of the drawing calls.
+//
+// Frame boundaries are made at each call to Present.
+type Recorder struct {
+	engine   render.Engine
+	frames   []Frame
+	current  Frame
+	textures map[render.Texturer]string // texture names for Copy
+}
+
+// New creates a Recorder around an engine. The engine does the actual
+// drawing; use the software engine for headless recording.
+func New(engine render.Engine) *Recorder {
+	return &Recorder{
+		engine:   engine,
+		textures: map[render.Texturer]string{},
+	}
+}
+
+// Engine returns the wrapped render.Engine.
+func (r *Recorder) Engine() render.Engine {
+	return r.engine
+}
+
+// List returns the DisplayList recorded so far. If commands were recorded
+// since the last Present, they are included as the final frame.
+func (r *Recorder) List() *DisplayList {
+	var list = &DisplayList{
+		Frames: make([]Frame, 0, len(r.frames)+1),
+	}
+	list.Frames = append(list.Frames, r.frames...)
+	if len(r.current) > 0 {
+		list.Frames = append(list.Frames, append(Frame{}, r.current...))
+	}
+	return list
+}
+
+// Reset forgets the recorded frames.
+//
+// Textures stored before the reset are still known to the Recorder, but a
+// replay of the new recording won't have their images unless they're stored
+// again.
+func (r *Recorder) Reset() {
+	r.frames = nil
+	r.current = nil
+}
+
+// push records a command to the current frame.
+func (r *Recorder) push(cmd Command) {
+	r.current = append(r.current, cmd)
+}
+
+// Setup the wrapped engine.
+func (r *Recorder) Setup() error {
+	return r.engine.Setup()
+}
+
+// Poll for events from the wrapped engine.
+func (r *Recorder) Poll() (*event.State, error) {
+	return r.engine.Poll()
+}
+
+// GetTicks from the wrapped engine.
+func (r *Recorder) GetTicks() uint32 {
+	return r.engine.GetTicks()
+}
+
+// WindowSize of the wrapped engine.
+func (r *Recorder) WindowSize() (w, h int) {
+	return r.engine.WindowSize()
+}
+
+// Present the frame and begin recording the next one.
+func (r *Recorder) Present() error {
+	r.push(Command{Op: OpPresent})
+	r.frames = append(r.frames, r.current)
+	r.current = nil
+	return r.engine.Present()
+}
+
+// Clear the canvas and set this color.
+func (r *Recorder) Clear(color render.Color) {
+	r.push(Command{Op: OpClear, Color: &color})
+	r.engine.Clear(color)
+}
+
+// SetTitle sets the window title.
+func (r *Recorder) SetTitle(title string) {
+	r.push(Command{Op: OpSetTitle, Title: title})
+	r.engine.SetTitle(title)
+}
+
+// DrawPoint puts a color at a pixel.
+func (r *Recorder) DrawPoint(color render.Color, point render.Point) {
+	r.push(Command{Op: OpDrawPoint, Color: &color, Points: []render.Point{point}})
+	r.engine.DrawPoint(color, point)
+}
+
+// DrawLine draws a line between two points.
+func (r *Recorder) DrawLine(color render.Color, a, b render.Point) {
+	r.push(Command{Op: OpDrawLine, Color: &color, Points: []render.Point{a, b}})
+	r.engine.DrawLine(color, a, b)
+}
+
+// DrawRect draws a rectangle.
+func (r *Recorder) DrawRect(color render.Color, rect render.Rect) {
+	r.push(Command{Op: OpDrawRect, Color: &color, Rect: &rect})
+	r.engine.DrawRect(color, rect)
+}
+
+// DrawBox draws a filled rectangle.
+func (r *Recorder) DrawBox(color render.Color, rect render.Rect) {
+	r.push(Command{Op: OpDrawBox, Color: &color, Rect: &rect})
+	r.engine.DrawBox(color, rect)
+}
+
+// DrawLineAA draws an anti-aliased line.
+func (r *Recorder) DrawLineAA(color render.Color, x1, y1, x2, y2 float64) {
+	r.push(Command{Op: OpDrawLineAA, Color: &color, Coords: []float64{x1, y1, x2, y2}})
+	r.engine.DrawLineAA(color, x1, y1, x2, y2)
+}
+
+// DrawPolyline draws connected lines with a stroke style.
+func (r *Recorder) DrawPolyline(color render.Color, style render.StrokeStyle, points []render.Point) {
+	r.push(Command{
+		Op:     OpDrawPolyline,
+		Color:  &color,
+		Stroke: &style,
+		Points: append([]render.Point{}, points...),
+	})
+	r.engine.DrawPolyline(color, style, points)
+}
+
+// DrawPolygon draws the outline of a polygon.
+func (r *Recorder) DrawPolygon(color render.Color, points []render.Point) {
+	r.push(Command{
+		Op:     OpDrawPolygon,
+		Color:  &color,
+		Points: append([]render.Point{}, points...),
+	})
+	r.engine.DrawPolygon(color, points)
+}
+
+// FillPolygon draws a filled polygon.
+func (r *Recorder) FillPolygon(color render.Color, points []render.Point, rule render.FillRule) {
+	r.push(Command{
+		Op:     OpFillPolygon,
+		Color:  &color,
+		Points: append([]render.Point{}, points...),
+		Rule:   rule,
+	})
+	r.engine.FillPolygon(color, points, rule)
+}
+
+// DrawEllipse draws the outline of an ellipse.
+func (r *Recorder) DrawEllipse(color render.Color, rect render.Rect) {
+	r.push(Command{Op: OpDrawEllipse, Color: &color, Rect: &rect})
+	r.engine.DrawEllipse(color, rect)
+}
+
+// FillEllipse draws a filled ellipse.
+func (r *Recorder) FillEllipse(color render.Color, rect render.Rect) {
+	r.push(Command{Op: OpFillEllipse, Color: &color, Rect: &rect})
+	r.engine.FillEllipse(color, rect)
+}
+
+// DrawCircle draws the outline of a circle. The radius is recorded as the
+// single coordinate.
+func (r *Recorder) DrawCircle(color render.Color, center render.Point, radius int) {
+	r.push(Command{
+		Op:     OpDrawCircle,
+		Color:  &color,
+		Points: []render.Point{center},
+		Coords: []float64{float64(radius)},
+	})
+	r.engine.DrawCircle(color, center, radius)
+}
+
+// DrawArc draws part of the outline of an ellipse. The start and end angles
+// are recorded as the coordinates.
+func (r *Recorder) DrawArc(color render.Color, rect render.Rect, start, end float64) {
+	r.push(Command{
+		Op:     OpDrawArc,
+		Color:  &color,
+		Rect:   &rect,
+		Coords: []float64{start, end},
+	})
+	r.engine.DrawArc(color, rect, start, end)
+}
+
+// DrawQuadBezier draws a quadratic Bezier curve.
+func (r *Recorder) DrawQuadBezier(color render.Color, a, b, c render.Point) {
+	r.push(Command{Op: OpDrawQuadBezier, Color: &color, Points: []render.Point{a, b, c}})
+	r.engine.DrawQuadBezier(color, a, b, c)
+}
+
+// DrawCubicBezier draws a cubic Bezier curve.
+func (r *Recorder) DrawCubicBezier(color render.Color, a, b, c, d render.Point) {
+	r.push(Command{Op: OpDrawCubicBezier, Color: &color, Points: []render.Point{a, b, c, d}})
+	r.engine.DrawCubicBezier(color, a, b, c, d)
+}
+
+// DrawText draws text.
+func (r *Recorder) DrawText(text render.Text, point render.Point) error {
+	r.push(Command{Op: OpDrawText, Text: &text, Points: []render.Point{point}})
+	return r.engine.DrawText(text, point)
+}
+
+// ComputeTextRect computes the size of text using the wrapped engine.
+func (r *Recorder) ComputeTextRect(text render.Text) (render.Rect, error) {
+	return r.engine.ComputeTextRect(text)
+}
+
+// PushClip limits drawing to the inside of the rect.
+func (r *Recorder) PushClip(rect render.Rect) {
+	r.push(Command{Op: OpPushClip, Rect: &rect})
+	r.engine.PushClip(rect)
+}
+
+// PopClip restores the previous clip rect.
+func (r *Recorder) PopClip() {
+	r.push(Command{Op: OpPopClip})
+	r.engine.PopClip()
+}
+
+// PushTransform transforms the coordinates of the drawing functions.
+func (r *Recorder) PushTransform(m render.Matrix) {
+	r.push(Command{Op: OpPushTransform, Matrix: &m})
+	r.engine.PushTransform(m)
+}
+
+// PopTransform restores the previous transform.
+func (r *Recorder) PopTransform() {
+	r.push(Command{Op: OpPopTransform})
+	r.engine.PopTransform()
+}
+
+// SetBlendMode sets how the drawing functions mix colors with the screen.
+func (r *Recorder) SetBlendMode(mode render.BlendMode) {
+	r.push(Command{Op: OpSetBlendMode, Blend: mode})
+	r.engine.SetBlendMode(mode)
+}
+
+// SetOpacity scales the alpha of everything drawn.
+func (r *Recorder) SetOpacity(opacity float64) {
+	r.push(Command{Op: OpSetOpacity, Coords: []float64{opacity}})
+	r.engine.SetOpacity(opacity)
+}
+
+// StoreTexture caches a texture with the wrapped engine. The image is
+// recorded as a PNG so the texture can be recreated on replay.
+func (r *Recorder) StoreTexture(name string, img image.Image) (render.Texturer, error) {
+	var fh = bytes.NewBuffer([]byte{})
+	if err := png.Encode(fh, img); err != nil {
+		return nil, fmt.Errorf("StoreTexture(%s): png.Encode: %s", name, err)
+	}
+
+	tex, err := r.engine.StoreTexture(name, img)
+	if err != nil {
+		return nil, err
+	}
+
+	r.push(Command{Op: OpStoreTexture, Texture: name, Image: fh.Bytes()})
+	r.textures[tex] = name
+	return tex, nil
+}
+
+// LoadTexture recalls a cached texture from the wrapped engine.
+func (r *Recorder) LoadTexture(name string) (render.Texturer, error) {
+	tex, err := r.engine.LoadTexture(name)
+	if err != nil {
+		return nil, err
+	}
+
+	r.textures[tex] = name
+	return tex, nil
+}
+
+// Copy a texture onto the canvas.
+//
+// Only textures that went through this Recorder's StoreTexture or
+// LoadTexture can be named in the recording; others are drawn but recorded
+// without a name, and are skipped on replay.
+func (r *Recorder) Copy(t render.Texturer, src, dst render.Rect) {
+	r.push(Command{Op: OpCopy, Texture: r.textures[t], Src: &src, Rect: &dst})
+	r.engine.Copy(t, src, dst)
+}
+
+// FreeTextures frees all textures of the wrapped engine.
+func (r *Recorder) FreeTextures() int {
+	r.push(Command{Op: OpFreeTextures})
+	r.textures = map[render.Texturer]string{}
+	return r.engine.FreeTextures()
+}
+
+// Delay using the wrapped engine.
+func (r *Recorder) Delay(time uint32) {
+	r.engine.Delay(time)
+}
+
+// Teardown the wrapped engine.
+func (r *Recorder) Teardown() {
+	r.engine.Teardown()
+}
+
+// Loop calls the wrapped engine's Loop.
+func (r *Recorder) Loop() error {
+	return r.engine.Loop()
+}
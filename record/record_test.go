@@ -0,0 +1,159 @@
+package record_test
+
+import (
+	"bytes"
+	"image"
+	"math"
+	"testing"
+
+	"git.kirsle.net/go/render"
+	"git.kirsle.net/go/render/record"
+	"git.kirsle.net/go/render/software"
+)
+
+// The Recorder must satisfy the render.Engine interface.
+var _ render.Engine = &record.Recorder{}
+
+// drawScene draws two frames of a small test scene.
+func drawScene(t *testing.T, e render.Engine) {
+	var img = image.NewRGBA(image.Rect(0, 0, 4, 4))
+	for i := 0; i < 4; i++ {
+		img.Set(i, i, render.Magenta.ToColor())
+	}
+
+	tex, err := e.StoreTexture("diagonal.png", img)
+	if err != nil {
+		t.Fatalf("StoreTexture: %s", err)
+	}
+
+	// Frame 1.
+	e.Clear(render.White)
+	e.DrawBox(render.SkyBlue, render.Rect{X: 2, Y: 2, W: 10, H: 6})
+	e.Present()
+
+	// Frame 2.
+	e.Clear(render.Black)
+	e.DrawRect(render.Red, render.Rect{X: 1, Y: 1, W: 30, H: 30})
+	e.DrawLine(render.Green, render.NewPoint(0, 31), render.NewPoint(31, 0))
+	e.DrawPoint(render.Yellow, render.NewPoint(16, 16))
+	e.PushClip(render.Rect{X: 20, Y: 4, W: 6, H: 6})
+	e.Copy(tex, tex.Size(), render.Rect{X: 20, Y: 4, W: 8, H: 8})
+	e.PopClip()
+	e.PushTransform(render.Translation(16, 16).Rotate(math.Pi / 4))
+	e.DrawBox(render.Blue, render.Rect{X: -4, Y: -4, W: 8, H: 8})
+	e.PopTransform()
+	e.SetBlendMode(render.BlendAdditive)
+	e.SetOpacity(0.5)
+	e.DrawBox(render.Red, render.Rect{X: 0, Y: 24, W: 32, H: 4})
+	e.SetBlendMode(render.BlendAlpha)
+	e.SetOpacity(1)
+	e.DrawBox(render.RGBA(0, 255, 0, 128), render.Rect{X: 8, Y: 20, W: 16, H: 8})
+	e.Present()
+}
+
+func TestRecordReplay(t *testing.T) {
+	var (
+		original = software.New(32, 32)
+		recorder = record.New(original)
+	)
+	drawScene(t, recorder)
+
+	list := recorder.List()
+	if len(list.Frames) != 2 {
+		t.Fatalf("expected 2 frames, got %d", len(list.Frames))
+	}
+	if last := list.Frames[0][len(list.Frames[0])-1]; last.Op != record.OpPresent {
+		t.Errorf("expected the frame to end with Present, got %s", last.Op)
+	}
+
+	// Round trip through JSON.
+	var buf bytes.Buffer
+	if err := list.Save(&buf); err != nil {
+		t.Fatalf("Save: %s", err)
+	}
+	loaded, err := record.Load(&buf)
+	if err != nil {
+		t.Fatalf("Load: %s", err)
+	}
+	if loaded.Len() != list.Len() {
+		t.Errorf("loaded list has %d commands, expected %d", loaded.Len(), list.Len())
+	}
+
+	// Replay onto a fresh engine and compare the final frames.
+	replayed := software.New(32, 32)
+	if err := record.Replay(replayed, loaded); err != nil {
+		t.Fatalf("Replay: %s", err)
+	}
+	if !bytes.Equal(original.Image().Pix, replayed.Image().Pix) {
+		t.Errorf("replayed frame buffer differs from the original")
+	}
+}
+
+func TestRecordTranslucent(t *testing.T) {
+	// Translucent colors keep their alpha through JSON.
+	var (
+		color    = render.RGBA(255, 0, 0, 128)
+		recorder = record.New(software.New(8, 8))
+	)
+	recorder.DrawPoint(color, render.NewPoint(1, 1))
+	recorder.DrawText(render.Text{
+		Text:   "Hello",
+		Size:   12,
+		Color:  color,
+		Stroke: render.RGBA(0, 0, 255, 64),
+		Shadow: render.RGBA(0, 0, 0, 32),
+	}, render.NewPoint(0, 0))
+
+	var buf bytes.Buffer
+	if err := recorder.List().Save(&buf); err != nil {
+		t.Fatalf("Save: %s", err)
+	}
+	loaded, err := record.Load(&buf)
+	if err != nil {
+		t.Fatalf("Load: %s", err)
+	}
+
+	var (
+		expect = recorder.List().Frames[0]
+		frame  = loaded.Frames[0]
+	)
+	if *frame[0].Color != color {
+		t.Errorf("DrawPoint: expected %s, got %s", color, *frame[0].Color)
+	}
+	if *frame[1].Text != *expect[1].Text {
+		t.Errorf("DrawText: expected %+v, got %+v", *expect[1].Text, *frame[1].Text)
+	}
+}
+
+func TestPlayerSeek(t *testing.T) {
+	var (
+		original = software.New(32, 32)
+		recorder = record.New(original)
+	)
+	drawScene(t, recorder)
+
+	// Play only the first frame.
+	replayed := software.New(32, 32)
+	player := record.NewPlayer(replayed, recorder.List())
+	if err := player.Seek(1); err != nil {
+		t.Fatalf("Seek: %s", err)
+	}
+	if player.Done() {
+		t.Errorf("player should have one more frame to play")
+	}
+
+	actual := render.FromColor(replayed.Image().At(4, 4))
+	if actual != render.SkyBlue {
+		t.Errorf("after the first frame: expected %s, got %s", render.SkyBlue, actual)
+	}
+
+	if err := player.Next(); err != nil {
+		t.Fatalf("Next: %s", err)
+	}
+	if !bytes.Equal(original.Image().Pix, replayed.Image().Pix) {
+		t.Errorf("replayed frame buffer differs from the original")
+	}
+	if err := player.Next(); err == nil {
+		t.Errorf("expected an error playing past the last frame")
+	}
+}
@@ -0,0 +1,218 @@
+package record
+
+import (
+	"bytes"
+	"fmt"
+	"image/png"
+
+	"git.kirsle.net/go/render"
+)
+
+// Player replays a DisplayList against a render.Engine one frame at a time.
+type Player struct {
+	engine   render.Engine
+	list     *DisplayList
+	frame    int                        // index of the next frame to play
+	textures map[string]render.Texturer // textures stored during the replay
+}
+
+// NewPlayer prepares to replay the DisplayList on an engine.
+func NewPlayer(engine render.Engine, list *DisplayList) *Player {
+	return &Player{
+		engine:   engine,
+		list:     list,
+		textures: map[string]render.Texturer{},
+	}
+}
+
+// Replay the whole DisplayList on an engine.
+func Replay(engine render.Engine, list *DisplayList) error {
+	player := NewPlayer(engine, list)
+	for !player.Done() {
+		if err := player.Next(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Frame returns the index of the next frame to be played.
+func (p *Player) Frame() int {
+	return p.frame
+}
+
+// Done returns whether every frame has been played.
+func (p *Player) Done() bool {
+	return p.frame >= len(p.list.Frames)
+}
+
+// Next plays the next frame.
+func (p *Player) Next() error {
+	if p.Done() {
+		return fmt.Errorf("Player.Next: no more frames to play")
+	}
+
+	frame := p.list.Frames[p.frame]
+	p.frame++
+
+	for i, cmd := range frame {
+		if err := p.Exec(cmd); err != nil {
+			return fmt.Errorf("frame %d command %d (%s): %s", p.frame-1, i, cmd.Op, err)
+		}
+	}
+	return nil
+}
+
+// Seek plays frames until the given frame index is the next one to be
+// played. Seeking backwards starts over from the first frame.
+func (p *Player) Seek(frame int) error {
+	if frame < p.frame {
+		p.frame = 0
+		p.textures = map[string]render.Texturer{}
+	}
+
+	for p.frame < frame && !p.Done() {
+		if err := p.Next(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Exec runs a single command on the engine.
+func (p *Player) Exec(cmd Command) error {
+	var e = p.engine
+
+	// Validate the arguments needed by the Op.
+	var (
+		needColor  bool
+		needPoints int
+		needRect   bool
+		needCoords int
+	)
+	switch cmd.Op {
+	case OpClear:
+		needColor = true
+	case OpDrawPoint:
+		needColor, needPoints = true, 1
+	case OpDrawLine:
+		needColor, needPoints = true, 2
+	case OpDrawRect, OpDrawBox, OpDrawEllipse, OpFillEllipse:
+		needColor, needRect = true, true
+	case OpDrawCircle:
+		needColor, needPoints, needCoords = true, 1, 1
+	case OpDrawQuadBezier:
+		needColor, needPoints = true, 3
+	case OpDrawCubicBezier:
+		needColor, needPoints = true, 4
+	case OpDrawArc:
+		needColor, needRect, needCoords = true, true, 2
+	case OpDrawLineAA:
+		needColor, needCoords = true, 4
+	case OpDrawPolyline, OpDrawPolygon, OpFillPolygon:
+		needColor = true
+	case OpDrawText:
+		needPoints = 1
+	case OpSetOpacity:
+		needCoords = 1
+	case OpCopy, OpPushClip:
+		needRect = true
+	}
+	if needColor && cmd.Color == nil {
+		return fmt.Errorf("missing color")
+	}
+	if len(cmd.Points) < needPoints {
+		return fmt.Errorf("expected %d points, got %d", needPoints, len(cmd.Points))
+	}
+	if needRect && cmd.Rect == nil {
+		return fmt.Errorf("missing rect")
+	}
+	if len(cmd.Coords) < needCoords {
+		return fmt.Errorf("expected %d coords, got %d", needCoords, len(cmd.Coords))
+	}
+
+	switch cmd.Op {
+	case OpClear:
+		e.Clear(*cmd.Color)
+	case OpSetTitle:
+		e.SetTitle(cmd.Title)
+	case OpDrawPoint:
+		e.DrawPoint(*cmd.Color, cmd.Points[0])
+	case OpDrawLine:
+		e.DrawLine(*cmd.Color, cmd.Points[0], cmd.Points[1])
+	case OpDrawRect:
+		e.DrawRect(*cmd.Color, *cmd.Rect)
+	case OpDrawBox:
+		e.DrawBox(*cmd.Color, *cmd.Rect)
+	case OpDrawLineAA:
+		e.DrawLineAA(*cmd.Color, cmd.Coords[0], cmd.Coords[1], cmd.Coords[2], cmd.Coords[3])
+	case OpDrawPolyline:
+		if cmd.Stroke == nil {
+			return fmt.Errorf("missing stroke")
+		}
+		e.DrawPolyline(*cmd.Color, *cmd.Stroke, cmd.Points)
+	case OpDrawPolygon:
+		e.DrawPolygon(*cmd.Color, cmd.Points)
+	case OpFillPolygon:
+		e.FillPolygon(*cmd.Color, cmd.Points, cmd.Rule)
+	case OpDrawEllipse:
+		e.DrawEllipse(*cmd.Color, *cmd.Rect)
+	case OpFillEllipse:
+		e.FillEllipse(*cmd.Color, *cmd.Rect)
+	case OpDrawCircle:
+		e.DrawCircle(*cmd.Color, cmd.Points[0], int(cmd.Coords[0]))
+	case OpDrawArc:
+		e.DrawArc(*cmd.Color, *cmd.Rect, cmd.Coords[0], cmd.Coords[1])
+	case OpDrawQuadBezier:
+		e.DrawQuadBezier(*cmd.Color, cmd.Points[0], cmd.Points[1], cmd.Points[2])
+	case OpDrawCubicBezier:
+		e.DrawCubicBezier(*cmd.Color, cmd.Points[0], cmd.Points[1], cmd.Points[2], cmd.Points[3])
+	case OpDrawText:
+		if cmd.Text == nil {
+			return fmt.Errorf("missing text")
+		}
+		return e.DrawText(*cmd.Text, cmd.Points[0])
+	case OpPushClip:
+		e.PushClip(*cmd.Rect)
+	case OpPopClip:
+		e.PopClip()
+	case OpPushTransform:
+		if cmd.Matrix == nil {
+			return fmt.Errorf("missing matrix")
+		}
+		e.PushTransform(*cmd.Matrix)
+	case OpPopTransform:
+		e.PopTransform()
+	case OpSetBlendMode:
+		e.SetBlendMode(cmd.Blend)
+	case OpSetOpacity:
+		e.SetOpacity(cmd.Coords[0])
+	case OpStoreTexture:
+		img, err := png.Decode(bytes.NewReader(cmd.Image))
+		if err != nil {
+			return fmt.Errorf("texture %s: %s", cmd.Texture, err)
+		}
+
+		tex, err := e.StoreTexture(cmd.Texture, img)
+		if err != nil {
+			return err
+		}
+		p.textures[cmd.Texture] = tex
+	case OpCopy:
+		tex, ok := p.textures[cmd.Texture]
+		if !ok || cmd.Src == nil {
+			// Texture wasn't named in the recording.
+			return nil
+		}
+		e.Copy(tex, *cmd.Src, *cmd.Rect)
+	case OpFreeTextures:
+		e.FreeTextures()
+		p.textures = map[string]render.Texturer{}
+	case OpPresent:
+		return e.Present()
+	default:
+		return fmt.Errorf("unknown op")
+	}
+
+	return nil
+}
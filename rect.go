@@ -43,25 +43,145 @@ func (r Rect) Bigger(other Rect) bool {
 }
 
 // Intersects with the other rectangle in any way.
+//
+// Like Point.Inside, the edges are inclusive: two rects that share an edge
+// are considered to intersect. Use Intersection to find the overlapping area.
 func (r Rect) Intersects(other Rect) bool {
-	// Do a bidirectional compare.
-	compare := func(a, b Rect) bool {
-		var corners = []Point{
-			NewPoint(b.X, b.Y),
-			NewPoint(b.X, b.Y+b.H),
-			NewPoint(b.X+b.W, b.Y),
-			NewPoint(b.X+b.W, b.Y+b.H),
-			NewPoint(b.X+b.W/2, b.Y+b.H/2), // midpoint
-		}
-		for _, pt := range corners {
-			if pt.Inside(a) {
-				return true
-			}
-		}
-		return false
-	}
-
-	return compare(r, other) || compare(other, r) || false
+	var (
+		a = r.Normalize()
+		b = other.Normalize()
+	)
+	return a.X <= b.X+b.W && b.X <= a.X+a.W &&
+		a.Y <= b.Y+b.H && b.Y <= a.Y+a.H
+}
+
+// Intersection returns the area where the two rectangles overlap.
+//
+// The rects cover the pixels from X to X+W-1 and Y to Y+H-1, so rects that
+// merely share an edge have no intersection. When there is no overlap, the
+// zero Rect is returned; check it with IsEmpty.
+func (r Rect) Intersection(other Rect) Rect {
+	var (
+		a  = r.Normalize()
+		b  = other.Normalize()
+		x1 = maxInt(a.X, b.X)
+		y1 = maxInt(a.Y, b.Y)
+		x2 = minInt(a.X+a.W, b.X+b.W)
+		y2 = minInt(a.Y+a.H, b.Y+b.H)
+	)
+	if x2 <= x1 || y2 <= y1 {
+		return Rect{}
+	}
+	return Rect{
+		X: x1,
+		Y: y1,
+		W: x2 - x1,
+		H: y2 - y1,
+	}
+}
+
+// Union returns the smallest rect that contains both rectangles. An empty
+// rect doesn't contribute to the union.
+func (r Rect) Union(other Rect) Rect {
+	var (
+		a = r.Normalize()
+		b = other.Normalize()
+	)
+	if a.IsEmpty() {
+		return b
+	} else if b.IsEmpty() {
+		return a
+	}
+
+	var (
+		x1 = minInt(a.X, b.X)
+		y1 = minInt(a.Y, b.Y)
+		x2 = maxInt(a.X+a.W, b.X+b.W)
+		y2 = maxInt(a.Y+a.H, b.Y+b.H)
+	)
+	return Rect{
+		X: x1,
+		Y: y1,
+		W: x2 - x1,
+		H: y2 - y1,
+	}
+}
+
+// Contains returns whether the other rect lies entirely inside this one.
+// An empty rect is contained by any rect.
+func (r Rect) Contains(other Rect) bool {
+	var (
+		a = r.Normalize()
+		b = other.Normalize()
+	)
+	if b.IsEmpty() {
+		return true
+	}
+	return b.X >= a.X && b.X+b.W <= a.X+a.W &&
+		b.Y >= a.Y && b.Y+b.H <= a.Y+a.H
+}
+
+// Difference returns the parts of this rect not covered by the other one,
+// as up to four non-overlapping rects: a full width band above and below the
+// other rect, and the pieces left and right of it.
+func (r Rect) Difference(other Rect) []Rect {
+	var a = r.Normalize()
+	if a.IsEmpty() {
+		return nil
+	}
+
+	var clip = a.Intersection(other)
+	if clip.IsEmpty() {
+		return []Rect{a}
+	}
+
+	var result = make([]Rect, 0, 4)
+
+	// Above.
+	if clip.Y > a.Y {
+		result = append(result, Rect{X: a.X, Y: a.Y, W: a.W, H: clip.Y - a.Y})
+	}
+
+	// Below.
+	if bottom := clip.Y + clip.H; bottom < a.Y+a.H {
+		result = append(result, Rect{X: a.X, Y: bottom, W: a.W, H: a.Y + a.H - bottom})
+	}
+
+	// Left.
+	if clip.X > a.X {
+		result = append(result, Rect{X: a.X, Y: clip.Y, W: clip.X - a.X, H: clip.H})
+	}
+
+	// Right.
+	if right := clip.X + clip.W; right < a.X+a.W {
+		result = append(result, Rect{X: right, Y: clip.Y, W: a.X + a.W - right, H: clip.H})
+	}
+
+	return result
+}
+
+// Normalize returns the rect with a positive width and height, moving the
+// X,Y point to the top-left corner if W or H was negative.
+func (r Rect) Normalize() Rect {
+	if r.W < 0 {
+		r.X += r.W
+		r.W = -r.W
+	}
+	if r.H < 0 {
+		r.Y += r.H
+		r.H = -r.H
+	}
+	return r
+}
+
+// IsEmpty returns if the Rect has no area.
+func (r Rect) IsEmpty() bool {
+	return r.W == 0 || r.H == 0
+}
+
+// Area returns the number of pixels covered by the rect.
+func (r Rect) Area() int {
+	return AbsInt(r.W * r.H)
 }
 
 // IsZero returns if the Rect is uninitialized.
@@ -1,6 +1,7 @@
 package render_test
 
 import (
+	"fmt"
 	"strconv"
 	"testing"
 
@@ -52,7 +53,7 @@ func TestIntersection(t *testing.T) {
 		{
 			A:      newRect(183, 256, 283, 356),
 			B:      newRect(0, -240, 874, 490),
-			Expect: false, // XXX: must be true
+			Expect: false, // B ends at Y=250, above A
 		},
 		{
 			A:      newRect(0, 30, 9, 62),
@@ -64,6 +65,18 @@ func TestIntersection(t *testing.T) {
 			B:      newRect(7, 4, 17, 28),
 			Expect: true,
 		},
+		{
+			// Cross shape: no corner of either rect is inside the other.
+			A:      newRect(40, 0, 20, 100),
+			B:      newRect(0, 40, 100, 20),
+			Expect: true,
+		},
+		{
+			// Negative width and height.
+			A:      newRect(100, 100, -50, -50),
+			B:      newRect(60, 60, 10, 10),
+			Expect: true,
+		},
 	}
 
 	for _, test := range tests {
@@ -79,3 +92,83 @@ func TestIntersection(t *testing.T) {
 		}
 	}
 }
+
+func TestRectAlgebra(t *testing.T) {
+	newRect := func(x, y, w, h int) render.Rect {
+		return render.Rect{
+			X: x,
+			Y: y,
+			W: w,
+			H: h,
+		}
+	}
+
+	var (
+		A     = newRect(0, 0, 100, 100)
+		cross = newRect(40, -10, 20, 120)
+	)
+
+	var tests = []struct {
+		Name   string
+		Actual render.Rect
+		Expect render.Rect
+	}{
+		{"intersection", A.Intersection(newRect(50, 50, 100, 100)), newRect(50, 50, 50, 50)},
+		{"intersection inside", A.Intersection(newRect(10, 10, 5, 5)), newRect(10, 10, 5, 5)},
+		{"intersection cross", A.Intersection(cross), newRect(40, 0, 20, 100)},
+		{"intersection touching edges", A.Intersection(newRect(100, 0, 10, 10)), render.Rect{}},
+		{"intersection apart", A.Intersection(newRect(200, 200, 10, 10)), render.Rect{}},
+		{"intersection negative", A.Intersection(newRect(110, 110, -20, -20)), newRect(90, 90, 10, 10)},
+		{"union", A.Union(newRect(150, 50, 10, 100)), newRect(0, 0, 160, 150)},
+		{"union empty", A.Union(render.Rect{}), A},
+		{"normalize", newRect(10, 10, -10, -5).Normalize(), newRect(0, 5, 10, 5)},
+	}
+	for _, test := range tests {
+		if test.Actual != test.Expect {
+			t.Errorf("%s: expected %s, got %s", test.Name, test.Expect, test.Actual)
+		}
+	}
+
+	// Contains.
+	if !A.Contains(newRect(0, 0, 100, 100)) || !A.Contains(newRect(10, 10, 1, 1)) {
+		t.Errorf("Contains: expected rects inside %s to be contained", A)
+	}
+	if A.Contains(newRect(90, 90, 11, 5)) || A.Contains(cross) {
+		t.Errorf("Contains: expected rects overflowing %s to not be contained", A)
+	}
+
+	// Difference.
+	var diffTests = []struct {
+		Name   string
+		B      render.Rect
+		Expect []render.Rect
+	}{
+		{"hole", newRect(25, 25, 50, 50), []render.Rect{
+			newRect(0, 0, 100, 25),
+			newRect(0, 75, 100, 25),
+			newRect(0, 25, 25, 50),
+			newRect(75, 25, 25, 50),
+		}},
+		{"cross", cross, []render.Rect{
+			newRect(0, 0, 40, 100),
+			newRect(60, 0, 40, 100),
+		}},
+		{"covered", newRect(-10, -10, 200, 200), []render.Rect{}},
+		{"apart", newRect(200, 200, 10, 10), []render.Rect{A}},
+	}
+	for _, test := range diffTests {
+		actual := A.Difference(test.B)
+		if fmt.Sprint(actual) != fmt.Sprint(test.Expect) {
+			t.Errorf("Difference %s: expected %v, got %v", test.Name, test.Expect, actual)
+		}
+
+		// The pieces plus the intersection must add up to the original area.
+		var area = A.Intersection(test.B).Area()
+		for _, piece := range actual {
+			area += piece.Area()
+		}
+		if area != A.Area() {
+			t.Errorf("Difference %s: pieces cover %d pixels, expected %d", test.Name, area, A.Area())
+		}
+	}
+}
@@ -0,0 +1,190 @@
+package render
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// RectF has a floating point coordinate and a width and height, for the
+// bounding boxes of objects that move by less than a pixel at a time.
+//
+// A RectF covers the area from X to X+W and Y to Y+H.
+type RectF struct {
+	X float64
+	Y float64
+	W float64
+	H float64
+}
+
+func (r RectF) String() string {
+	return fmt.Sprintf("RectF<%g,%g,%g,%g>",
+		r.X, r.Y, r.W, r.H,
+	)
+}
+
+// ParseRectF parses a rect from the "X,Y,W,H" text of MarshalText.
+func ParseRectF(v string) (RectF, error) {
+	parts := strings.Split(v, ",")
+	if len(parts) != 4 {
+		return RectF{}, fmt.Errorf("'%s': not a valid rect string", v)
+	}
+
+	var values [4]float64
+	for i, part := range parts {
+		value, err := strconv.ParseFloat(strings.TrimSpace(part), 64)
+		if err != nil {
+			return RectF{}, fmt.Errorf("'%s': invalid rect string: %s", v, err)
+		}
+		values[i] = value
+	}
+	return RectF{
+		X: values[0],
+		Y: values[1],
+		W: values[2],
+		H: values[3],
+	}, nil
+}
+
+// RectF converts the rect to floating point, without any loss.
+func (r Rect) RectF() RectF {
+	return RectF{
+		X: float64(r.X),
+		Y: float64(r.Y),
+		W: float64(r.W),
+		H: float64(r.H),
+	}
+}
+
+// Rect rounds the edges of the rect to the nearest whole pixels, so that
+// rects which touch still touch afterwards. A RectF made from a Rect
+// converts back to the same Rect.
+func (r RectF) Rect() Rect {
+	r = r.Normalize()
+	var (
+		x1 = round(r.X)
+		y1 = round(r.Y)
+	)
+	return Rect{
+		X: x1,
+		Y: y1,
+		W: round(r.X+r.W) - x1,
+		H: round(r.Y+r.H) - y1,
+	}
+}
+
+// Point returns the rectangle's X,Y values as a PointF.
+func (r RectF) Point() PointF {
+	return PointF{
+		X: r.X,
+		Y: r.Y,
+	}
+}
+
+// Center returns the point in the middle of the rect.
+func (r RectF) Center() PointF {
+	return PointF{
+		X: r.X + r.W/2,
+		Y: r.Y + r.H/2,
+	}
+}
+
+// AddPoint returns the rect moved by a point.
+func (r RectF) AddPoint(other PointF) RectF {
+	return RectF{
+		X: r.X + other.X,
+		Y: r.Y + other.Y,
+		W: r.W,
+		H: r.H,
+	}
+}
+
+// Intersection returns the area where the two rectangles overlap, or the
+// zero RectF if they don't. Check it with IsEmpty.
+func (r RectF) Intersection(other RectF) RectF {
+	var (
+		a  = r.Normalize()
+		b  = other.Normalize()
+		x1 = math.Max(a.X, b.X)
+		y1 = math.Max(a.Y, b.Y)
+		x2 = math.Min(a.X+a.W, b.X+b.W)
+		y2 = math.Min(a.Y+a.H, b.Y+b.H)
+	)
+	if x2 <= x1 || y2 <= y1 {
+		return RectF{}
+	}
+	return RectF{
+		X: x1,
+		Y: y1,
+		W: x2 - x1,
+		H: y2 - y1,
+	}
+}
+
+// Union returns the smallest rect that contains both rectangles. An empty
+// rect doesn't contribute to the union.
+func (r RectF) Union(other RectF) RectF {
+	var (
+		a = r.Normalize()
+		b = other.Normalize()
+	)
+	if a.IsEmpty() {
+		return b
+	} else if b.IsEmpty() {
+		return a
+	}
+
+	var (
+		x1 = math.Min(a.X, b.X)
+		y1 = math.Min(a.Y, b.Y)
+		x2 = math.Max(a.X+a.W, b.X+b.W)
+		y2 = math.Max(a.Y+a.H, b.Y+b.H)
+	)
+	return RectF{
+		X: x1,
+		Y: y1,
+		W: x2 - x1,
+		H: y2 - y1,
+	}
+}
+
+// Normalize returns the rect with a positive width and height, moving the
+// X,Y point to the top-left corner if W or H was negative.
+func (r RectF) Normalize() RectF {
+	if r.W < 0 {
+		r.X += r.W
+		r.W = -r.W
+	}
+	if r.H < 0 {
+		r.Y += r.H
+		r.H = -r.H
+	}
+	return r
+}
+
+// IsEmpty returns if the RectF has no area.
+func (r RectF) IsEmpty() bool {
+	return r.W == 0 || r.H == 0
+}
+
+// MarshalText converts the rect into "X,Y,W,H" text for JSON.
+func (r RectF) MarshalText() ([]byte, error) {
+	var parts = []string{
+		strconv.FormatFloat(r.X, 'g', -1, 64),
+		strconv.FormatFloat(r.Y, 'g', -1, 64),
+		strconv.FormatFloat(r.W, 'g', -1, 64),
+		strconv.FormatFloat(r.H, 'g', -1, 64),
+	}
+	return []byte(strings.Join(parts, ",")), nil
+}
+
+// UnmarshalText to restore it from text.
+func (r *RectF) UnmarshalText(b []byte) error {
+	rect, err := ParseRectF(strings.Trim(string(b), `"`))
+	if err != nil {
+		return err
+	}
+	*r = rect
+	return nil
+}
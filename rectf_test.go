@@ -0,0 +1,76 @@
+package render_test
+
+import (
+	"testing"
+
+	"git.kirsle.net/go/render"
+)
+
+func TestRectF(t *testing.T) {
+	var (
+		a = render.RectF{X: 0.5, Y: 0.5, W: 10, H: 10}
+		b = render.RectF{X: 5.5, Y: -2, W: 10, H: 5}
+	)
+
+	if expect, actual := (render.RectF{X: 5.5, Y: 0.5, W: 5, H: 2.5}), a.Intersection(b); actual != expect {
+		t.Errorf("Intersection: expected %s, got %s", expect, actual)
+	}
+	if actual := a.Intersection(b.AddPoint(render.NewPointF(20, 0))); !actual.IsEmpty() {
+		t.Errorf("Intersection: expected no overlap, got %s", actual)
+	}
+	if expect, actual := (render.RectF{X: 0.5, Y: -2, W: 15, H: 12.5}), a.Union(b); actual != expect {
+		t.Errorf("Union: expected %s, got %s", expect, actual)
+	}
+	if expect, actual := render.NewPointF(5.5, 5.5), a.Center(); actual != expect {
+		t.Errorf("Center: expected %s, got %s", expect, actual)
+	}
+
+	// The left and top edges are inside, the right and bottom are not.
+	var tests = []struct {
+		Point  render.PointF
+		Expect bool
+	}{
+		{render.NewPointF(0.5, 0.5), true},
+		{render.NewPointF(10.4, 10.4), true},
+		{render.NewPointF(10.5, 5), false},
+		{render.NewPointF(5, 0.4), false},
+	}
+	for _, test := range tests {
+		if actual := test.Point.Inside(a); actual != test.Expect {
+			t.Errorf("%s inside %s: expected %v, got %v", test.Point, a, test.Expect, actual)
+		}
+	}
+}
+
+func TestRectFConvert(t *testing.T) {
+	for _, r := range []render.Rect{{X: 1, Y: 2, W: 3, H: 4}, {X: -10, Y: -10, W: 0, H: 7}} {
+		if actual := r.RectF().Rect(); actual != r {
+			t.Errorf("%s: expected to convert back to itself, got %s", r, actual)
+		}
+	}
+
+	// Touching rects still touch after rounding.
+	var (
+		left  = render.RectF{X: 0.3, Y: 0, W: 4.4, H: 1}.Rect()
+		right = render.RectF{X: 4.7, Y: 0, W: 4.4, H: 1}.Rect()
+	)
+	if left.X+left.W != right.X {
+		t.Errorf("Rect: expected %s and %s to touch", left, right)
+	}
+
+	var r = render.RectF{X: 0.5, Y: -1, W: 2.25, H: 1e10}
+	text, err := r.MarshalText()
+	if err != nil {
+		t.Fatalf("MarshalText: %s", err)
+	}
+	var loaded render.RectF
+	if err := loaded.UnmarshalText(text); err != nil {
+		t.Fatalf("UnmarshalText(%s): %s", text, err)
+	}
+	if loaded != r {
+		t.Errorf("UnmarshalText(%s): expected %s, got %s", text, r, loaded)
+	}
+	if err := loaded.UnmarshalText([]byte("1,2,3")); err == nil {
+		t.Errorf("UnmarshalText: expected an error for a short rect")
+	}
+}
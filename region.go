@@ -0,0 +1,133 @@
+package render
+
+import "fmt"
+
+// Region is an area made up of a set of non-overlapping rectangles, useful
+// for tracking dirty regions of the screen or building clip areas.
+//
+// The zero value is an empty Region ready to use.
+type Region struct {
+	rects []Rect
+}
+
+// NewRegion creates a Region covering the union of the rects.
+func NewRegion(rects ...Rect) *Region {
+	var region = &Region{}
+	for _, rect := range rects {
+		region.Add(rect)
+	}
+	return region
+}
+
+func (r *Region) String() string {
+	return fmt.Sprintf("Region<%v>", r.rects)
+}
+
+// Rects returns a copy of the non-overlapping rects that make up the region.
+func (r *Region) Rects() []Rect {
+	var result = make([]Rect, len(r.rects))
+	copy(result, r.rects)
+	return result
+}
+
+// IsEmpty returns if the region covers no area.
+func (r *Region) IsEmpty() bool {
+	return len(r.rects) == 0
+}
+
+// Reset the region to be empty.
+func (r *Region) Reset() {
+	r.rects = r.rects[:0]
+}
+
+// Area returns the number of pixels covered by the region.
+func (r *Region) Area() int {
+	var area int
+	for _, rect := range r.rects {
+		area += rect.Area()
+	}
+	return area
+}
+
+// Bounds returns the smallest rect that contains the whole region.
+func (r *Region) Bounds() Rect {
+	var bounds Rect
+	for _, rect := range r.rects {
+		bounds = bounds.Union(rect)
+	}
+	return bounds
+}
+
+// Add a rect to the region. Only the parts of the rect not already covered
+// by the region are added, so the rects stay non-overlapping.
+func (r *Region) Add(rect Rect) {
+	var pieces = []Rect{rect.Normalize()}
+	if pieces[0].IsEmpty() {
+		return
+	}
+
+	for _, existing := range r.rects {
+		var next []Rect
+		for _, piece := range pieces {
+			next = append(next, piece.Difference(existing)...)
+		}
+		pieces = next
+		if len(pieces) == 0 {
+			return
+		}
+	}
+
+	r.rects = append(r.rects, pieces...)
+}
+
+// AddRegion adds all of another region's area to this one.
+func (r *Region) AddRegion(other *Region) {
+	for _, rect := range other.rects {
+		r.Add(rect)
+	}
+}
+
+// Subtract a rect's area from the region.
+func (r *Region) Subtract(rect Rect) {
+	var result = make([]Rect, 0, len(r.rects))
+	for _, existing := range r.rects {
+		result = append(result, existing.Difference(rect)...)
+	}
+	r.rects = result
+}
+
+// Clip the region to only the area inside the rect.
+func (r *Region) Clip(rect Rect) {
+	var result = r.rects[:0]
+	for _, existing := range r.rects {
+		if clip := existing.Intersection(rect); !clip.IsEmpty() {
+			result = append(result, clip)
+		}
+	}
+	r.rects = result
+}
+
+// Contains returns whether the pixel at the point is inside the region.
+//
+// Unlike Point.Inside, the far edges of the rects are not included: a rect
+// covers the pixels from X to X+W-1, the same area as Rect.Intersection and
+// Area measure.
+func (r *Region) Contains(p Point) bool {
+	for _, rect := range r.rects {
+		if p.X >= rect.X && p.X < rect.X+rect.W &&
+			p.Y >= rect.Y && p.Y < rect.Y+rect.H {
+			return true
+		}
+	}
+	return false
+}
+
+// Intersects returns whether any part of the rect overlaps the region.
+func (r *Region) Intersects(rect Rect) bool {
+	for _, existing := range r.rects {
+		if !existing.Intersection(rect).IsEmpty() {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,78 @@
+package render_test
+
+import (
+	"testing"
+
+	"git.kirsle.net/go/render"
+)
+
+func TestRegion(t *testing.T) {
+	var region = render.NewRegion(
+		render.Rect{X: 0, Y: 0, W: 10, H: 10},
+		render.Rect{X: 5, Y: 5, W: 10, H: 10}, // overlaps the first by 5x5
+	)
+
+	if area := region.Area(); area != 175 {
+		t.Errorf("expected an area of 175, got %d", area)
+	}
+	if bounds := region.Bounds(); bounds != (render.Rect{X: 0, Y: 0, W: 15, H: 15}) {
+		t.Errorf("expected bounds 0,0,15,15, got %s", bounds)
+	}
+
+	// The rects must never overlap.
+	var rects = region.Rects()
+	for i := range rects {
+		for j := i + 1; j < len(rects); j++ {
+			if !rects[i].Intersection(rects[j]).IsEmpty() {
+				t.Errorf("rects %s and %s overlap", rects[i], rects[j])
+			}
+		}
+	}
+
+	// Adding an area already covered changes nothing.
+	region.Add(render.Rect{X: 2, Y: 2, W: 4, H: 4})
+	if area := region.Area(); area != 175 {
+		t.Errorf("after adding a covered rect: expected an area of 175, got %d", area)
+	}
+
+	var containTests = []struct {
+		P      render.Point
+		Expect bool
+	}{
+		{render.NewPoint(0, 0), true},
+		{render.NewPoint(14, 14), true},
+		{render.NewPoint(12, 2), false},
+		{render.NewPoint(9, 2), true},
+		{render.NewPoint(10, 2), false}, // the far edge isn't included
+		{render.NewPoint(2, 10), false},
+		{render.NewPoint(15, 15), false},
+	}
+	for _, test := range containTests {
+		if actual := region.Contains(test.P); actual != test.Expect {
+			t.Errorf("Contains(%s): expected %t, got %t", test.P, test.Expect, actual)
+		}
+	}
+
+	// Punch a hole in the middle.
+	region.Subtract(render.Rect{X: 6, Y: 6, W: 2, H: 2})
+	if area := region.Area(); area != 171 {
+		t.Errorf("after Subtract: expected an area of 171, got %d", area)
+	}
+	if region.Contains(render.NewPoint(7, 7)) {
+		t.Errorf("after Subtract: 7,7 should not be in the region")
+	}
+	if region.Intersects(render.Rect{X: 6, Y: 6, W: 2, H: 2}) {
+		t.Errorf("after Subtract: the hole should not intersect the region")
+	}
+
+	// Clip to the top-left quarter.
+	region.Clip(render.Rect{X: 0, Y: 0, W: 5, H: 5})
+	if area := region.Area(); area != 25 {
+		t.Errorf("after Clip: expected an area of 25, got %d", area)
+	}
+
+	region.Reset()
+	if !region.IsEmpty() {
+		t.Errorf("after Reset: expected an empty region")
+	}
+}
@@ -0,0 +1,217 @@
+// Package rendertest provides golden image testing for code that draws on a
+// render.Engine.
+//
+// A test hands a draw function to Golden, which renders it headlessly with
+// the software engine and compares the result against a PNG image stored in
+// the testdata directory:
+//
+//	func TestButton(t *testing.T) {
+//		rendertest.Golden(t, "button", rendertest.Options{}, func(e render.Engine) {
+//			e.DrawBox(render.Grey, render.Rect{X: 10, Y: 10, W: 80, H: 24})
+//		})
+//	}
+//
+// Run `go test -rendertest.update` to write (or rewrite) the golden images. When a
+// comparison fails, the actual image and a visual diff are written next to
+// the golden image as NAME.actual.png and NAME.diff.png.
+package rendertest
+
+import (
+	"flag"
+	"fmt"
+	"image"
+	"image/color"
+	"image/png"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"git.kirsle.net/go/render"
+	"git.kirsle.net/go/render/software"
+)
+
+// Update the golden images instead of comparing against them. The flag name
+// is prefixed with the package name so it doesn't clash with an -update flag
+// of the tests that import it.
+var update = flag.Bool("rendertest.update", false, "update the golden images of rendertest")
+
+// Default options.
+var (
+	DefaultWidth  = 100
+	DefaultHeight = 100
+	DefaultDir    = "testdata"
+)
+
+// Options configure a golden image test. The zero value uses the defaults.
+type Options struct {
+	Width  int // engine size; default is DefaultWidth x DefaultHeight
+	Height int
+	Dir    string // where the golden images live; default is DefaultDir
+
+	// Background color to clear the engine with before drawing. The zero
+	// value (Invisible) leaves the frame buffer transparent.
+	Background render.Color
+
+	// Tolerance is the largest difference allowed in any one color channel
+	// of a pixel before it counts as mismatched.
+	Tolerance uint8
+
+	// MaxMismatched is the number of mismatched pixels allowed before the
+	// test fails.
+	MaxMismatched int
+}
+
+// Golden renders the draw function and compares the result against the
+// golden image named NAME.png.
+func Golden(t testing.TB, name string, opts Options, draw func(render.Engine)) {
+	t.Helper()
+	opts = opts.withDefaults()
+
+	var (
+		actual   = Render(opts.Width, opts.Height, opts.Background, draw)
+		filename = filepath.Join(opts.Dir, name+".png")
+	)
+
+	if *update {
+		if err := os.MkdirAll(opts.Dir, 0755); err != nil {
+			t.Fatalf("rendertest: %s", err)
+		}
+		if err := WritePNG(filename, actual); err != nil {
+			t.Fatalf("rendertest: %s", err)
+		}
+		return
+	}
+
+	expected, err := ReadPNG(filename)
+	if err != nil {
+		t.Fatalf("rendertest: %s (run `go test -rendertest.update` to create the golden image)", err)
+	}
+
+	diff, mismatched := Compare(expected, actual, opts.Tolerance)
+	if diff == nil {
+		t.Fatalf("rendertest: %s: image size %s does not match the golden image size %s",
+			name, actual.Bounds().Size(), expected.Bounds().Size(),
+		)
+	}
+
+	if mismatched > opts.MaxMismatched {
+		var (
+			actualFile = filepath.Join(opts.Dir, name+".actual.png")
+			diffFile   = filepath.Join(opts.Dir, name+".diff.png")
+		)
+		if err := WritePNG(actualFile, actual); err != nil {
+			t.Errorf("rendertest: %s", err)
+		}
+		if err := WritePNG(diffFile, diff); err != nil {
+			t.Errorf("rendertest: %s", err)
+		}
+		t.Errorf("rendertest: %s: %d pixels differ from the golden image (tolerance %d, max %d); see %s",
+			name, mismatched, opts.Tolerance, opts.MaxMismatched, diffFile,
+		)
+	}
+}
+
+// Render draws with a new software engine and returns its frame buffer.
+func Render(width, height int, background render.Color, draw func(render.Engine)) *image.RGBA {
+	e := software.New(width, height)
+	e.Clear(background)
+	draw(e)
+	e.Present()
+	return e.Image()
+}
+
+// Compare two images pixel by pixel.
+//
+// It returns a visual diff image, where matching pixels are a faded copy of
+// the expected image and mismatched pixels are bright red, along with the
+// number of pixels whose channels differ by more than the tolerance.
+//
+// If the images are not the same size, the diff image is nil.
+func Compare(expected, actual image.Image, tolerance uint8) (*image.RGBA, int) {
+	var (
+		eb = expected.Bounds()
+		ab = actual.Bounds()
+	)
+	if eb.Size() != ab.Size() {
+		return nil, 0
+	}
+
+	var (
+		diff       = image.NewRGBA(image.Rect(0, 0, eb.Dx(), eb.Dy()))
+		mismatched int
+	)
+	for y := 0; y < eb.Dy(); y++ {
+		for x := 0; x < eb.Dx(); x++ {
+			var (
+				want = color.NRGBAModel.Convert(expected.At(eb.Min.X+x, eb.Min.Y+y)).(color.NRGBA)
+				got  = color.NRGBAModel.Convert(actual.At(ab.Min.X+x, ab.Min.Y+y)).(color.NRGBA)
+			)
+
+			if channelDelta(want.R, got.R) > tolerance ||
+				channelDelta(want.G, got.G) > tolerance ||
+				channelDelta(want.B, got.B) > tolerance ||
+				channelDelta(want.A, got.A) > tolerance {
+				mismatched++
+				diff.Set(x, y, color.NRGBA{R: 255, A: 255})
+				continue
+			}
+
+			// Faded greyscale of the expected pixel.
+			grey := uint8((uint32(want.R)+uint32(want.G)+uint32(want.B))/3*uint32(want.A)/255/4 + 191)
+			diff.Set(x, y, color.NRGBA{R: grey, G: grey, B: grey, A: 255})
+		}
+	}
+
+	return diff, mismatched
+}
+
+// ReadPNG reads a PNG image from disk.
+func ReadPNG(filename string) (image.Image, error) {
+	fh, err := os.Open(filename)
+	if err != nil {
+		return nil, err
+	}
+	defer fh.Close()
+
+	img, err := png.Decode(fh)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %s", filename, err)
+	}
+	return img, nil
+}
+
+// WritePNG writes an image to disk as a PNG.
+func WritePNG(filename string, img image.Image) error {
+	fh, err := os.Create(filename)
+	if err != nil {
+		return err
+	}
+
+	if err := png.Encode(fh, img); err != nil {
+		fh.Close()
+		return err
+	}
+	return fh.Close()
+}
+
+// withDefaults fills in the zero values of the options.
+func (o Options) withDefaults() Options {
+	if o.Width <= 0 {
+		o.Width = DefaultWidth
+	}
+	if o.Height <= 0 {
+		o.Height = DefaultHeight
+	}
+	if o.Dir == "" {
+		o.Dir = DefaultDir
+	}
+	return o
+}
+
+// channelDelta returns the absolute difference of two color channels.
+func channelDelta(a, b uint8) uint8 {
+	if a > b {
+		return a - b
+	}
+	return b - a
+}
@@ -0,0 +1,84 @@
+package rendertest_test
+
+import (
+	"image"
+	"image/color"
+	"testing"
+
+	"git.kirsle.net/go/render"
+	"git.kirsle.net/go/render/rendertest"
+	"git.kirsle.net/go/render/software"
+	"golang.org/x/image/font/gofont/goregular"
+)
+
+func TestGoldenShapes(t *testing.T) {
+	rendertest.Golden(t, "shapes", rendertest.Options{
+		Width:      64,
+		Height:     48,
+		Background: render.White,
+	}, func(e render.Engine) {
+		e.DrawBox(render.SkyBlue, render.Rect{X: 4, Y: 4, W: 24, H: 16})
+		e.DrawRect(render.DarkBlue, render.Rect{X: 4, Y: 4, W: 24, H: 16})
+		e.DrawLine(render.Red, render.NewPoint(32, 4), render.NewPoint(60, 44))
+		e.DrawBox(render.RGBA(0, 153, 0, 128), render.Rect{X: 16, Y: 12, W: 24, H: 24})
+	})
+}
+
+func TestGoldenText(t *testing.T) {
+	software.InstallFont("goregular.ttf", goregular.TTF)
+
+	rendertest.Golden(t, "text", rendertest.Options{
+		Width:  80,
+		Height: 24,
+	}, func(e render.Engine) {
+		e.DrawText(render.Text{
+			Text:         "Golden!",
+			Size:         16,
+			Color:        render.SkyBlue,
+			Shadow:       render.DarkBlue,
+			FontFilename: "goregular.ttf",
+		}, render.NewPoint(4, 2))
+	})
+}
+
+func TestCompare(t *testing.T) {
+	var (
+		expected = image.NewNRGBA(image.Rect(0, 0, 4, 4))
+		actual   = image.NewNRGBA(image.Rect(0, 0, 4, 4))
+	)
+	actual.Set(0, 0, color.NRGBA{R: 2, A: 2})
+	actual.Set(1, 1, color.NRGBA{R: 255, A: 255})
+	actual.Set(2, 2, color.NRGBA{G: 10, A: 10})
+
+	var tests = []struct {
+		Tolerance uint8
+		Expect    int
+	}{
+		{0, 3},
+		{2, 2},
+		{10, 1},
+		{255, 0},
+	}
+	for _, test := range tests {
+		diff, mismatched := rendertest.Compare(expected, actual, test.Tolerance)
+		if diff == nil {
+			t.Fatalf("Compare: expected a diff image")
+		}
+		if mismatched != test.Expect {
+			t.Errorf("Compare with tolerance %d: expected %d mismatched pixels, got %d",
+				test.Tolerance, test.Expect, mismatched,
+			)
+		}
+	}
+
+	// Mismatched pixels are red in the diff.
+	diff, _ := rendertest.Compare(expected, actual, 0)
+	if c := diff.RGBAAt(1, 1); c.R != 255 || c.G != 0 || c.B != 0 {
+		t.Errorf("diff image: expected red at 1,1, got %+v", c)
+	}
+
+	// Different sizes can't be compared.
+	if diff, _ := rendertest.Compare(expected, image.NewRGBA(image.Rect(0, 0, 2, 2)), 0); diff != nil {
+		t.Errorf("Compare: expected a nil diff for differently sized images")
+	}
+}
@@ -2,56 +2,281 @@
 package sdl
 
 import (
+	"math"
+
 	"git.kirsle.net/go/render"
 	"github.com/veandco/go-sdl2/sdl"
 )
 
 // Clear the canvas and set this color.
 func (r *Renderer) Clear(color render.Color) {
-	if color != r.lastColor {
-		r.renderer.SetDrawColor(color.Red, color.Green, color.Blue, color.Alpha)
-	}
+	r.renderer.SetDrawColor(color.Red, color.Green, color.Blue, color.Alpha)
 	r.renderer.Clear()
 }
 
 // DrawPoint puts a color at a pixel.
 func (r *Renderer) DrawPoint(color render.Color, point render.Point) {
-	if color != r.lastColor {
-		r.renderer.SetDrawColor(color.Red, color.Green, color.Blue, color.Alpha)
+	r.setDrawColor(color)
+
+	m := r.transform.Current()
+	if offset, ok := m.Offset(); ok {
+		r.renderer.DrawPoint(int32(point.X+offset.X), int32(point.Y+offset.Y))
+		return
 	}
-	r.renderer.DrawPoint(int32(point.X), int32(point.Y))
+	m.Boxes(render.Rect{X: point.X, Y: point.Y, W: 1, H: 1}, r.fillRect)
 }
 
 // DrawLine draws a line between two points.
 func (r *Renderer) DrawLine(color render.Color, a, b render.Point) {
-	if color != r.lastColor {
-		r.renderer.SetDrawColor(color.Red, color.Green, color.Blue, color.Alpha)
+	r.setDrawColor(color)
+
+	m := r.transform.Current()
+	if offset, ok := m.Offset(); ok {
+		a.Add(offset)
+		b.Add(offset)
+		r.renderer.DrawLine(int32(a.X), int32(a.Y), int32(b.X), int32(b.Y))
+		return
 	}
-	r.renderer.DrawLine(int32(a.X), int32(a.Y), int32(b.X), int32(b.Y))
+	m.LineBoxes(a, b, r.fillRect)
 }
 
 // DrawRect draws a rectangle.
 func (r *Renderer) DrawRect(color render.Color, rect render.Rect) {
-	if color != r.lastColor {
-		r.renderer.SetDrawColor(color.Red, color.Green, color.Blue, color.Alpha)
-	}
-	r.renderer.DrawRect(&sdl.Rect{
-		X: int32(rect.X),
-		Y: int32(rect.Y),
-		W: int32(rect.W),
-		H: int32(rect.H),
-	})
+	r.setDrawColor(color)
+
+	m := r.transform.Current()
+	if offset, ok := m.Offset(); ok {
+		var sdlRect = RectToSDL(rect.AddPoint(offset))
+		r.renderer.DrawRect(&sdlRect)
+		return
+	}
+	m.OutlineBoxes(rect, r.fillRect)
 }
 
 // DrawBox draws a filled rectangle.
 func (r *Renderer) DrawBox(color render.Color, rect render.Rect) {
-	if color != r.lastColor {
-		r.renderer.SetDrawColor(color.Red, color.Green, color.Blue, color.Alpha)
-	}
-	r.renderer.FillRect(&sdl.Rect{
-		X: int32(rect.X),
-		Y: int32(rect.Y),
-		W: int32(rect.W),
-		H: int32(rect.H),
+	r.setDrawColor(color)
+
+	m := r.transform.Current()
+	if offset, ok := m.Offset(); ok {
+		r.fillRect(rect.AddPoint(offset))
+		return
+	}
+	m.Boxes(rect, r.fillRect)
+}
+
+// DrawLineAA draws an anti-aliased line.
+//
+// Under a transform, the endpoints are transformed and the line is drawn
+// smoothly on the screen rather than as zoomed in pixels.
+func (r *Renderer) DrawLineAA(color render.Color, x1, y1, x2, y2 float64) {
+	m := r.transform.Current()
+	x1, y1 = m.Apply(x1, y1)
+	x2, y2 = m.Apply(x2, y2)
+	render.WuLinePoints(x1, y1, x2, y2)(func(pt render.Point, coverage float64) bool {
+		r.setDrawColor(color.ScaleAlpha(coverage))
+		r.renderer.DrawPoint(int32(pt.X), int32(pt.Y))
+		return true
+	})
+}
+
+// DrawPolyline draws connected lines with a stroke style.
+func (r *Renderer) DrawPolyline(color render.Color, style render.StrokeStyle, points []render.Point) {
+	r.fillSpans(color, style.Spans(points))
+}
+
+// DrawPolygon draws the outline of a polygon.
+func (r *Renderer) DrawPolygon(color render.Color, points []render.Point) {
+	r.fillSpans(color, render.PolygonOutline(points))
+}
+
+// FillPolygon draws a filled polygon.
+func (r *Renderer) FillPolygon(color render.Color, points []render.Point, rule render.FillRule) {
+	r.fillSpans(color, render.PolygonSpans(points, rule))
+}
+
+// DrawEllipse draws the outline of the ellipse that fits inside the rect.
+func (r *Renderer) DrawEllipse(color render.Color, rect render.Rect) {
+	r.fillSpans(color, render.EllipseOutline(rect))
+}
+
+// FillEllipse draws the filled ellipse that fits inside the rect.
+func (r *Renderer) FillEllipse(color render.Color, rect render.Rect) {
+	r.fillSpans(color, render.EllipseSpans(rect))
+}
+
+// DrawCircle draws the outline of a circle around a center pixel.
+func (r *Renderer) DrawCircle(color render.Color, center render.Point, radius int) {
+	r.DrawEllipse(color, render.Rect{
+		X: center.X - radius,
+		Y: center.Y - radius,
+		W: radius*2 + 1,
+		H: radius*2 + 1,
+	})
+}
+
+// DrawArc draws part of the outline of the ellipse that fits inside the rect,
+// going clockwise from the start to the end angle in radians.
+func (r *Renderer) DrawArc(color render.Color, rect render.Rect, start, end float64) {
+	r.fillSpans(color, render.ArcOutline(rect, start, end))
+}
+
+// DrawQuadBezier draws a quadratic Bezier curve from A to C, bending towards
+// the control point B.
+func (r *Renderer) DrawQuadBezier(color render.Color, a, b, c render.Point) {
+	render.QuadBezierPoints(a, b, c)(func(pt render.Point) bool {
+		r.DrawPoint(color, pt)
+		return true
+	})
+}
+
+// DrawCubicBezier draws a cubic Bezier curve from A to D, with control points
+// B and C.
+func (r *Renderer) DrawCubicBezier(color render.Color, a, b, c, d render.Point) {
+	render.CubicBezierPoints(a, b, c, d)(func(pt render.Point) bool {
+		r.DrawPoint(color, pt)
+		return true
 	})
 }
+
+// fillSpans fills rows of pixels under the current transform.
+func (r *Renderer) fillSpans(color render.Color, spans []render.Span) {
+	r.setDrawColor(color)
+
+	m := r.transform.Current()
+	for _, span := range spans {
+		m.Boxes(span.Rect(), r.fillRect)
+	}
+}
+
+// setDrawColor sets the draw color of the primitives, with its alpha scaled
+// by the opacity.
+func (r *Renderer) setDrawColor(color render.Color) {
+	if r.opacity != 255 {
+		color.Alpha = premultiply(color.Alpha, r.opacity)
+	}
+	if r.blendMode == render.BlendMultiply {
+		color.Red = premultiply(color.Red, color.Alpha)
+		color.Green = premultiply(color.Green, color.Alpha)
+		color.Blue = premultiply(color.Blue, color.Alpha)
+	}
+	r.renderer.SetDrawColor(color.Red, color.Green, color.Blue, color.Alpha)
+}
+
+// fillRect fills a rect on screen with the current draw color.
+func (r *Renderer) fillRect(rect render.Rect) {
+	var sdlRect = RectToSDL(rect)
+	r.renderer.FillRect(&sdlRect)
+}
+
+// PushClip limits drawing to the inside of the rect, intersected with any
+// clip already pushed.
+func (r *Renderer) PushClip(rect render.Rect) {
+	r.setClipRect(r.clip.Push(rect), true)
+}
+
+// PopClip restores the clip rect that was current before the last PushClip.
+func (r *Renderer) PopClip() {
+	r.setClipRect(r.clip.Pop())
+}
+
+// setClipRect applies the current clip rect to SDL, or removes it if the
+// clip is not enabled.
+func (r *Renderer) setClipRect(rect render.Rect, enabled bool) {
+	if !enabled {
+		r.renderer.SetClipRect(nil)
+		return
+	}
+
+	// An empty clip must hide everything, but some SDL versions turn clipping
+	// off for an empty rect; use a single pixel off screen instead.
+	if rect.IsEmpty() {
+		rect = render.Rect{X: -1, Y: -1, W: 1, H: 1}
+	}
+
+	var clip = RectToSDL(rect)
+	r.renderer.SetClipRect(&clip)
+}
+
+// PushTransform transforms the coordinates of the drawing functions by the
+// matrix, combined with any transform already pushed.
+func (r *Renderer) PushTransform(m render.Matrix) {
+	r.transform.Push(m)
+}
+
+// PopTransform restores the transform that was current before the last
+// PushTransform.
+func (r *Renderer) PopTransform() {
+	r.transform.Pop()
+}
+
+// SetBlendMode sets how the drawing functions mix colors with the screen.
+func (r *Renderer) SetBlendMode(mode render.BlendMode) {
+	r.blendMode = mode
+	if r.renderer != nil {
+		r.renderer.SetDrawBlendMode(BlendModeToSDL(mode))
+	}
+}
+
+// SetOpacity scales the alpha of everything drawn, from 0 to 1.
+func (r *Renderer) SetOpacity(opacity float64) {
+	r.opacity = uint8(math.Round(math.Max(0, math.Min(1, opacity)) * 255))
+}
+
+// copyTransformed copies a texture to the dst rect under the current
+// transform, blend mode and opacity.
+//
+// SDL can scale, flip and rotate textures but it can't skew them: a skewed
+// transform draws the texture rotated and scaled to the closest fit.
+func (r *Renderer) copyTransformed(tex *sdl.Texture, src *sdl.Rect, dst render.Rect) {
+	tex.SetBlendMode(BlendModeToSDL(r.blendMode))
+	tex.SetAlphaMod(r.opacity)
+	if r.blendMode == render.BlendMultiply {
+		// The texture is premultiplied, so fade its colors with the alpha.
+		tex.SetColorMod(r.opacity, r.opacity, r.opacity)
+	}
+
+	m := r.transform.Current()
+	if offset, ok := m.Offset(); ok {
+		var sdlRect = RectToSDL(dst.AddPoint(offset))
+		r.renderer.Copy(tex, src, &sdlRect)
+		return
+	}
+
+	// Mirrored transforms are drawn flipped.
+	var flip sdl.RendererFlip = sdl.FLIP_NONE
+	if m.IsAxisAligned() {
+		if m.A < 0 {
+			flip |= sdl.FLIP_HORIZONTAL
+		}
+		if m.D < 0 {
+			flip |= sdl.FLIP_VERTICAL
+		}
+
+		var sdlRect = RectToSDL(m.TransformRect(dst))
+		r.renderer.CopyEx(tex, src, &sdlRect, 0, nil, flip)
+		return
+	}
+
+	if m.A*m.D-m.B*m.C < 0 {
+		flip = sdl.FLIP_VERTICAL
+	}
+
+	// SDL rotates around the center of the destination rect.
+	var (
+		angle  = math.Atan2(m.B, m.A) * 180 / math.Pi
+		w      = float64(dst.W) * math.Hypot(m.A, m.B)
+		h      = float64(dst.H) * math.Hypot(m.C, m.D)
+		cx, cy = m.Apply(
+			float64(dst.X)+float64(dst.W)/2,
+			float64(dst.Y)+float64(dst.H)/2,
+		)
+		sdlRect = sdl.Rect{
+			X: int32(math.Round(cx - w/2)),
+			Y: int32(math.Round(cy - h/2)),
+			W: int32(math.Round(w)),
+			H: int32(math.Round(h)),
+		}
+	)
+	r.renderer.CopyEx(tex, src, &sdlRect, angle, nil, flip)
+}
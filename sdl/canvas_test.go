@@ -0,0 +1,49 @@
+package sdl
+
+import (
+	"testing"
+
+	"git.kirsle.net/go/render"
+	"github.com/veandco/go-sdl2/sdl"
+)
+
+// softwareRenderer creates a Renderer that draws into a surface in memory,
+// without a window.
+func softwareRenderer(t *testing.T, width, height int32) (*Renderer, *sdl.Surface) {
+	surface, err := sdl.CreateRGBSurfaceWithFormat(0, width, height, 32, sdl.PIXELFORMAT_RGBA32)
+	if err != nil {
+		t.Skipf("SDL is not available: %s", err)
+	}
+	renderer, err := sdl.CreateSoftwareRenderer(surface)
+	if err != nil {
+		surface.Free()
+		t.Skipf("SDL is not available: %s", err)
+	}
+
+	r := New(t.Name(), int(width), int(height))
+	r.renderer = renderer
+	r.SetBlendMode(render.BlendAlpha)
+	return r, surface
+}
+
+func TestDrawInvisibleAfterColor(t *testing.T) {
+	r, surface := softwareRenderer(t, 4, 4)
+	defer surface.Free()
+	defer r.renderer.Destroy()
+
+	r.Clear(render.White)
+	r.DrawPoint(render.Red, render.NewPoint(0, 0))
+
+	// Black at no opacity is the zero Color, and must not draw in red.
+	r.SetOpacity(0)
+	r.DrawPoint(render.Black, render.NewPoint(1, 1))
+	r.renderer.Present()
+
+	var (
+		pix = surface.Pixels()
+		i   = int(surface.Pitch) + 4
+	)
+	if actual := render.RGBA(pix[i], pix[i+1], pix[i+2], pix[i+3]); actual != render.White {
+		t.Errorf("expected the pixel to stay %s, got %s", render.White, actual)
+	}
+}
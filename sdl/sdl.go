@@ -28,8 +28,11 @@ type Renderer struct {
 	textures  map[string]*Texture // cached textures
 	textureMu sync.RWMutex
 
-	// Optimizations to minimize SDL calls.
-	lastColor render.Color
+	// Drawing state.
+	clip      render.ClipStack
+	transform render.TransformStack
+	blendMode render.BlendMode
+	opacity   uint8 // alpha modulation of everything drawn
 }
 
 // New creates the SDL renderer.
@@ -40,6 +43,7 @@ func New(title string, width, height int) *Renderer {
 		width:    int32(width),
 		height:   int32(height),
 		textures: map[string]*Texture{},
+		opacity:  255,
 	}
 }
 
@@ -81,7 +85,7 @@ func (r *Renderer) Setup() error {
 	if err != nil {
 		panic(err)
 	}
-	renderer.SetDrawBlendMode(sdl.BLENDMODE_BLEND)
+	renderer.SetDrawBlendMode(BlendModeToSDL(r.blendMode))
 	r.renderer = renderer
 
 	return nil
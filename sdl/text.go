@@ -121,18 +121,25 @@ func (r *Renderer) DrawText(text render.Text, point render.Point) error {
 		}
 		defer surface.Free()
 
-		if tex, err = r.renderer.CreateTextureFromSurface(surface); err != nil {
+		var src = surface
+		if r.blendMode == render.BlendMultiply {
+			if src, err = premultiplySurface(surface); err != nil {
+				return
+			}
+			defer src.Free()
+		}
+
+		if tex, err = r.renderer.CreateTextureFromSurface(src); err != nil {
 			return
 		}
 		defer tex.Destroy()
 
-		tmp := &sdl.Rect{
-			X: int32(point.X) + dx,
-			Y: int32(point.Y) + dy,
-			W: surface.W,
-			H: surface.H,
-		}
-		r.renderer.Copy(tex, nil, tmp)
+		r.copyTransformed(tex, nil, render.Rect{
+			X: point.X + int(dx),
+			Y: point.Y + int(dy),
+			W: int(surface.W),
+			H: int(surface.H),
+		})
 	}
 
 	// Does the text have a stroke around it?
@@ -13,11 +13,17 @@ import (
 // Copy a texture into the renderer.
 func (r *Renderer) Copy(t render.Texturer, src, dst render.Rect) {
 	if tex, ok := t.(*Texture); ok {
-		var (
-			a = RectToSDL(src)
-			b = RectToSDL(dst)
-		)
-		r.renderer.Copy(tex.tex, &a, &b)
+		var sdlTex = tex.tex
+		if r.blendMode == render.BlendMultiply {
+			premul, err := tex.premultiplied()
+			if err != nil {
+				return
+			}
+			sdlTex = premul
+		}
+
+		var a = RectToSDL(src)
+		r.copyTransformed(sdlTex, &a, dst)
 	}
 }
 
@@ -25,6 +31,7 @@ func (r *Renderer) Copy(t render.Texturer, src, dst render.Rect) {
 type Texture struct {
 	render *Renderer // backref to free them up thoroughly
 	tex    *sdl.Texture
+	premul *sdl.Texture // premultiplied copy for BlendMultiply, made when first needed
 	image  image.Image
 	width  int32
 	height int32
@@ -32,6 +39,35 @@ type Texture struct {
 
 // StoreTexture caches an SDL texture from a bitmap.
 func (r *Renderer) StoreTexture(name string, img image.Image) (render.Texturer, error) {
+	surface, err := imageSurface(img)
+	if err != nil {
+		return nil, err
+	}
+	defer surface.Free()
+
+	texture, err := r.renderer.CreateTextureFromSurface(surface)
+	if err != nil {
+		return nil, fmt.Errorf("NewBitmap: create texture: %s", err)
+	}
+
+	tex := &Texture{
+		render: r,
+		width:  surface.W,
+		height: surface.H,
+		tex:    texture,
+		image:  img,
+	}
+
+	r.textureMu.Lock()
+	r.textures[name] = tex
+	r.textureMu.Unlock()
+
+	return tex, nil
+}
+
+// imageSurface converts an image to an SDL surface, with white as the color
+// key.
+func imageSurface(img image.Image) (*sdl.Surface, error) {
 	var (
 		fh = bytes.NewBuffer([]byte{})
 	)
@@ -51,30 +87,35 @@ func (r *Renderer) StoreTexture(name string, img image.Image) (render.Texturer,
 	if err != nil {
 		return nil, fmt.Errorf("NewTexture: sdl.LoadBMPRW: %s", err)
 	}
-	defer surface.Free()
 
 	// TODO: chroma key color hardcoded to white here
 	key := sdl.MapRGB(surface.Format, 255, 255, 255)
 	surface.SetColorKey(true, key)
 
-	texture, err := r.renderer.CreateTextureFromSurface(surface)
-	if err != nil {
-		return nil, fmt.Errorf("NewBitmap: create texture: %s", err)
+	return surface, nil
+}
+
+// premultiplied returns the premultiplied copy of the texture that
+// BlendMultiply draws, creating it the first time.
+func (t *Texture) premultiplied() (*sdl.Texture, error) {
+	if t.premul != nil {
+		return t.premul, nil
 	}
 
-	tex := &Texture{
-		render: r,
-		width:  surface.W,
-		height: surface.H,
-		tex:    texture,
-		image:  img,
+	surface, err := imageSurface(t.image)
+	if err != nil {
+		return nil, err
 	}
+	defer surface.Free()
 
-	r.textureMu.Lock()
-	r.textures[name] = tex
-	r.textureMu.Unlock()
+	converted, err := premultiplySurface(surface)
+	if err != nil {
+		return nil, err
+	}
+	defer converted.Free()
 
-	return tex, nil
+	t.premul, err = t.render.renderer.CreateTextureFromSurface(converted)
+	return t.premul, err
 }
 
 // CountTextures is a custom function for the SDL2 Engine only that returns the
@@ -119,6 +160,10 @@ func (t *Texture) Free() error {
 		err = t.tex.Destroy()
 		t.tex = nil
 	}
+	if t.premul != nil {
+		t.premul.Destroy()
+		t.premul = nil
+	}
 
 	// Free up the cached texture too to garbage collect the image.Image cache etc.
 	for name, tex := range t.render.textures {
@@ -155,6 +200,9 @@ func (r *Renderer) FreeTextures() int {
 	for name, tex := range r.textures {
 		delete(r.textures, name)
 		tex.tex.Destroy()
+		if tex.premul != nil {
+			tex.premul.Destroy()
+		}
 	}
 	return num
 }
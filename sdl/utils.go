@@ -24,3 +24,60 @@ func RectToSDL(r render.Rect) sdl.Rect {
 		H: int32(r.H),
 	}
 }
+
+// BlendModeToSDL converts Doodle's BlendMode to an sdl.BlendMode.
+//
+// SDL before 2.0.12 has no multiply mode, so it's composed from blend factors,
+// which needs SDL 2.0.6. They work out to the formula of render.BlendMultiply
+// only for premultiplied colors, so the Renderer premultiplies what it draws
+// in that mode.
+func BlendModeToSDL(mode render.BlendMode) sdl.BlendMode {
+	switch mode {
+	case render.BlendNone:
+		return sdl.BLENDMODE_NONE
+	case render.BlendAdditive:
+		return sdl.BLENDMODE_ADD
+	case render.BlendModulate:
+		return sdl.BLENDMODE_MOD
+	case render.BlendMultiply:
+		return sdl.ComposeCustomBlendMode(
+			sdl.BLENDFACTOR_DST_COLOR, sdl.BLENDFACTOR_ONE_MINUS_SRC_ALPHA, sdl.BLENDOPERATION_ADD,
+			sdl.BLENDFACTOR_ZERO, sdl.BLENDFACTOR_ONE, sdl.BLENDOPERATION_ADD,
+		)
+	default:
+		return sdl.BLENDMODE_BLEND
+	}
+}
+
+// premultiplySurface returns a copy of a surface in the RGBA32 format with
+// its colors multiplied by their alpha, for the BlendMultiply blend factors.
+// Converting the surface turns its color key into transparent pixels.
+func premultiplySurface(surface *sdl.Surface) (*sdl.Surface, error) {
+	converted, err := surface.ConvertFormat(sdl.PIXELFORMAT_RGBA32, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := converted.Lock(); err != nil {
+		converted.Free()
+		return nil, err
+	}
+	defer converted.Unlock()
+
+	var pix = converted.Pixels()
+	for y := 0; y < int(converted.H); y++ {
+		var row = pix[y*int(converted.Pitch):]
+		for x := 0; x < int(converted.W); x++ {
+			var p = row[x*4 : x*4+4]
+			p[0] = premultiply(p[0], p[3])
+			p[1] = premultiply(p[1], p[3])
+			p[2] = premultiply(p[2], p[3])
+		}
+	}
+	return converted, nil
+}
+
+// premultiply multiplies a color channel by an alpha.
+func premultiply(v, alpha uint8) uint8 {
+	return uint8((uint32(v)*uint32(alpha) + 127) / 255)
+}
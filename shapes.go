@@ -4,21 +4,44 @@ import (
 	"math"
 )
 
+// The shape iterator functions come in two flavors:
+//
+// The Iter* functions return a channel that yields the points of the shape
+// from a goroutine. They are convenient with a `for range` loop, but every
+// point goes through an unbuffered channel, and breaking out of the loop early
+// leaks the goroutine forever.
+//
+// The *Points functions return an iterator function compatible with Go's
+// iter.Seq[Point], which calls yield for each point of the shape without
+// allocating, and stops early when yield returns false. With Go 1.23 or newer
+// they can be used in a `for range` loop:
+//
+//	for pt := range render.LinePoints(A, B) {
+//		engine.DrawPoint(render.Red, pt)
+//	}
+//
+// Or with older versions of Go, by calling the iterator with a callback:
+//
+//	render.LinePoints(A, B)(func(pt render.Point) bool {
+//		engine.DrawPoint(render.Red, pt)
+//		return true // keep going
+//	})
+
 // IterLine is a generator that returns the X,Y coordinates to draw a line.
 // https://en.wikipedia.org/wiki/Digital_differential_analyzer_(graphics_algorithm)
+//
+// Prefer LinePoints, which doesn't need a goroutine.
 func IterLine(p1 Point, p2 Point) chan Point {
-	var (
-		x1 = p1.X
-		y1 = p1.Y
-		x2 = p2.X
-		y2 = p2.Y
-	)
-	generator := make(chan Point)
+	return pointChan(LinePoints(p1, p2))
+}
 
-	go func() {
+// LinePoints iterates over the X,Y coordinates to draw a line. It yields the
+// same points as IterLine.
+func LinePoints(p1, p2 Point) func(yield func(Point) bool) {
+	return func(yield func(Point) bool) {
 		var (
-			dx = float64(x2 - x1)
-			dy = float64(y2 - y1)
+			dx = float64(p2.X - p1.X)
+			dy = float64(p2.Y - p1.Y)
 		)
 		var step float64
 		if math.Abs(dx) >= math.Abs(dy) {
@@ -27,70 +50,100 @@ func IterLine(p1 Point, p2 Point) chan Point {
 			step = math.Abs(dy)
 		}
 
+		// A line from a point to itself.
+		if step == 0 {
+			yield(p1)
+			return
+		}
+
 		dx = dx / step
 		dy = dy / step
-		x := float64(x1)
-		y := float64(y1)
+		x := float64(p1.X)
+		y := float64(p1.Y)
 		for i := 0; i <= int(step); i++ {
-			generator <- NewPoint(int(x), int(y))
+			if !yield(NewPoint(int(x), int(y))) {
+				return
+			}
 			x += dx
 			y += dy
 		}
-
-		close(generator)
-	}()
-
-	return generator
+	}
 }
 
 // IterRect loops through all the points forming a rectangle between the
 // top-left point and the bottom-right point.
+//
+// Prefer RectPoints, which doesn't need a goroutine.
 func IterRect(p1, p2 Point) chan Point {
-	generator := make(chan Point)
+	return pointChan(RectPoints(p1, p2))
+}
+
+// RectPoints iterates over all the points forming a rectangle between the
+// top-left point and the bottom-right point. Each point is yielded once, in
+// the same order as IterRect.
+func RectPoints(p1, p2 Point) func(yield func(Point) bool) {
+	return func(yield func(Point) bool) {
+		// A rectangle with no width or height is a line.
+		if p1.X == p2.X || p1.Y == p2.Y {
+			LinePoints(p1, p2)(yield)
+			return
+		}
 
-	go func() {
 		var (
-			TopLeft     = p1
-			BottomRight = p2
-			TopRight    = Point{
-				X: BottomRight.X,
-				Y: TopLeft.Y,
-			}
-			BottomLeft = Point{
-				X: TopLeft.X,
-				Y: BottomRight.Y,
-			}
-			dedupe = map[Point]interface{}{}
+			stepX = 1
+			stepY = 1
 		)
+		if p2.X < p1.X {
+			stepX = -1
+		}
+		if p2.Y < p1.Y {
+			stepY = -1
+		}
 
-		// Trace all four edges and yield it.
-		var edges = []struct {
-			A Point
-			B Point
-		}{
-			{TopLeft, TopRight},
-			{TopLeft, BottomLeft},
-			{BottomLeft, BottomRight},
-			{TopRight, BottomRight},
+		// Trace all four edges, skipping the corners that were already
+		// yielded by a previous edge.
+		// Top edge: TopLeft to TopRight.
+		for x := p1.X; x != p2.X+stepX; x += stepX {
+			if !yield(NewPoint(x, p1.Y)) {
+				return
+			}
 		}
-		for _, edge := range edges {
-			for pt := range IterLine(edge.A, edge.B) {
-				if _, ok := dedupe[pt]; !ok {
-					generator <- pt
-					dedupe[pt] = nil
-				}
+
+		// Left edge: TopLeft to BottomLeft.
+		for y := p1.Y + stepY; y != p2.Y+stepY; y += stepY {
+			if !yield(NewPoint(p1.X, y)) {
+				return
 			}
 		}
 
-		close(generator)
-	}()
+		// Bottom edge: BottomLeft to BottomRight.
+		for x := p1.X + stepX; x != p2.X+stepX; x += stepX {
+			if !yield(NewPoint(x, p2.Y)) {
+				return
+			}
+		}
 
-	return generator
+		// Right edge: TopRight to BottomRight.
+		for y := p1.Y + stepY; y != p2.Y; y += stepY {
+			if !yield(NewPoint(p2.X, y)) {
+				return
+			}
+		}
+	}
 }
 
 // IterEllipse iterates an Ellipse using two Points as the top-left and
 // bottom-right corners of a rectangle that encompasses the ellipse.
+//
+// Prefer EllipsePoints, which doesn't need a goroutine.
 func IterEllipse(A, B Point) chan Point {
+	return pointChan(EllipsePoints(A, B))
+}
+
+// EllipsePoints iterates an Ellipse using two Points as the top-left and
+// bottom-right corners of a rectangle that encompasses the ellipse. It yields
+// the same points as IterEllipse.
+func EllipsePoints(A, B Point) func(yield func(Point) bool) {
 	var (
 		width  = AbsInt(B.X - A.X)
 		height = AbsInt(B.Y - A.Y)
@@ -98,5 +151,21 @@ func IterEllipse(A, B Point) chan Point {
 		center = NewPoint(AbsInt(B.X-radius.X), AbsInt(B.Y-radius.Y))
 	)
 
-	return MidpointEllipse(center, radius)
+	return MidpointEllipsePoints(center, radius)
+}
+
+// pointChan runs an iterator function in a goroutine and sends its points
+// over a channel, which is closed at the end.
+func pointChan(seq func(yield func(Point) bool)) chan Point {
+	generator := make(chan Point)
+
+	go func() {
+		seq(func(pt Point) bool {
+			generator <- pt
+			return true
+		})
+		close(generator)
+	}()
+
+	return generator
 }
@@ -0,0 +1,163 @@
+package render_test
+
+import (
+	"fmt"
+	"testing"
+
+	"git.kirsle.net/go/render"
+)
+
+// collect the points of an iterator function into a slice.
+func collect(seq func(yield func(render.Point) bool)) []render.Point {
+	var result []render.Point
+	seq(func(pt render.Point) bool {
+		result = append(result, pt)
+		return true
+	})
+	return result
+}
+
+// drain the points of a channel generator into a slice.
+func drain(ch chan render.Point) []render.Point {
+	var result []render.Point
+	for pt := range ch {
+		result = append(result, pt)
+	}
+	return result
+}
+
+func TestShapePoints(t *testing.T) {
+	var (
+		p = render.NewPoint
+	)
+
+	var tests = []struct {
+		Name   string
+		Seq    func(yield func(render.Point) bool)
+		Chan   chan render.Point
+		Expect []render.Point
+	}{
+		{
+			Name:   "diagonal line",
+			Seq:    render.LinePoints(p(0, 0), p(3, 3)),
+			Chan:   render.IterLine(p(0, 0), p(3, 3)),
+			Expect: []render.Point{p(0, 0), p(1, 1), p(2, 2), p(3, 3)},
+		},
+		{
+			Name:   "single point line",
+			Seq:    render.LinePoints(p(5, 5), p(5, 5)),
+			Chan:   render.IterLine(p(5, 5), p(5, 5)),
+			Expect: []render.Point{p(5, 5)},
+		},
+		{
+			Name: "rect",
+			Seq:  render.RectPoints(p(0, 0), p(2, 2)),
+			Chan: render.IterRect(p(0, 0), p(2, 2)),
+			Expect: []render.Point{
+				p(0, 0), p(1, 0), p(2, 0), // top
+				p(0, 1), p(0, 2), // left
+				p(1, 2), p(2, 2), // bottom
+				p(2, 1), // right
+			},
+		},
+		{
+			Name:   "flat rect",
+			Seq:    render.RectPoints(p(0, 0), p(2, 0)),
+			Chan:   render.IterRect(p(0, 0), p(2, 0)),
+			Expect: []render.Point{p(0, 0), p(1, 0), p(2, 0)},
+		},
+		{
+			Name: "circle",
+			Seq:  render.MidpointEllipsePoints(p(0, 0), p(1, 1)),
+			Chan: render.MidpointEllipse(p(0, 0), p(1, 1)),
+			Expect: []render.Point{
+				p(1, 0), p(1, 0), p(-1, 0), p(-1, 0),
+				p(0, 1), p(0, -1), p(0, 1), p(0, -1),
+			},
+		},
+		{
+			Name:   "straight quadratic curve",
+			Seq:    render.QuadBezierPoints(p(0, 0), p(2, 2), p(4, 4)),
+			Chan:   render.IterQuadBezier(p(0, 0), p(2, 2), p(4, 4)),
+			Expect: []render.Point{p(0, 0), p(1, 1), p(2, 2), p(3, 3), p(4, 4)},
+		},
+		{
+			Name:   "straight cubic curve",
+			Seq:    render.CubicBezierPoints(p(0, 0), p(1, 0), p(2, 0), p(3, 0)),
+			Chan:   render.IterCubicBezier(p(0, 0), p(1, 0), p(2, 0), p(3, 0)),
+			Expect: []render.Point{p(0, 0), p(1, 0), p(2, 0), p(3, 0)},
+		},
+	}
+
+	for _, test := range tests {
+		var (
+			seq = collect(test.Seq)
+			ch  = drain(test.Chan)
+		)
+		if fmt.Sprint(seq) != fmt.Sprint(test.Expect) {
+			t.Errorf("%s: expected %v, got %v", test.Name, test.Expect, seq)
+		}
+		if fmt.Sprint(ch) != fmt.Sprint(seq) {
+			t.Errorf("%s: channel yielded %v, iterator yielded %v", test.Name, ch, seq)
+		}
+	}
+}
+
+func TestShapePointsStopEarly(t *testing.T) {
+	var count int
+	render.EllipsePoints(render.NewPoint(0, 0), render.NewPoint(100, 100))(func(pt render.Point) bool {
+		count++
+		return count < 10
+	})
+	if count != 10 {
+		t.Errorf("expected the iterator to stop after 10 points, got %d", count)
+	}
+}
+
+func BenchmarkIterLine(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		for range render.IterLine(render.NewPoint(0, 0), render.NewPoint(1920, 1080)) {
+		}
+	}
+}
+
+func BenchmarkLinePoints(b *testing.B) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		render.LinePoints(render.NewPoint(0, 0), render.NewPoint(1920, 1080))(func(render.Point) bool {
+			return true
+		})
+	}
+}
+
+func BenchmarkIterRect(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		for range render.IterRect(render.NewPoint(0, 0), render.NewPoint(800, 600)) {
+		}
+	}
+}
+
+func BenchmarkRectPoints(b *testing.B) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		render.RectPoints(render.NewPoint(0, 0), render.NewPoint(800, 600))(func(render.Point) bool {
+			return true
+		})
+	}
+}
+
+func BenchmarkIterEllipse(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		for range render.IterEllipse(render.NewPoint(0, 0), render.NewPoint(1000, 800)) {
+		}
+	}
+}
+
+func BenchmarkEllipsePoints(b *testing.B) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		render.EllipsePoints(render.NewPoint(0, 0), render.NewPoint(1000, 800))(func(render.Point) bool {
+			return true
+		})
+	}
+}
@@ -0,0 +1,195 @@
+package render
+
+// SimplifyPolyline removes the points of a polyline that don't change its
+// shape by more than a tolerance in pixels, with the Douglas-Peucker
+// algorithm. It's useful to shrink a freehand stroke recorded from the
+// mouse, which has many points along nearly straight lines.
+//
+// The first and last points are always kept, so closed polylines stay
+// closed. A tolerance of zero removes only points that lie exactly on a
+// straight line, and repeated points.
+func SimplifyPolyline(points []Point, tolerance float64) []Point {
+	points = dedupePoints(points)
+	if len(points) < 3 {
+		return points
+	}
+
+	var (
+		keep  = make([]bool, len(points))
+		stack = [][2]int{{0, len(points) - 1}}
+	)
+	keep[0] = true
+	keep[len(points)-1] = true
+
+	// Keep the point furthest from the line between the ends of each range,
+	// if it's beyond the tolerance, and look again on either side of it.
+	for len(stack) > 0 {
+		var r = stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+
+		var (
+			a        = floatPoint(points[r[0]])
+			b        = floatPoint(points[r[1]])
+			furthest = -1
+			distance = tolerance
+		)
+		for i := r[0] + 1; i < r[1]; i++ {
+			if d := distanceToSegment(floatPoint(points[i]), a, b); d > distance {
+				furthest, distance = i, d
+			}
+		}
+		if furthest >= 0 {
+			keep[furthest] = true
+			stack = append(stack, [2]int{r[0], furthest}, [2]int{furthest, r[1]})
+		}
+	}
+
+	var result = make([]Point, 0, len(points))
+	for i, pt := range points {
+		if keep[i] {
+			result = append(result, pt)
+		}
+	}
+	return result
+}
+
+// SmoothCatmullRom returns a smooth curve that passes through every point of
+// a polyline, as a Catmull-Rom spline flattened into a polyline that strays
+// by no more than a tolerance in pixels from the true curve. A tolerance of
+// zero uses a quarter of a pixel, like the Bezier curve functions.
+//
+// If the last point is the same as the first, the polyline is closed and
+// the curve is smooth all the way around.
+func SmoothCatmullRom(points []Point, tolerance float64) []Point {
+	points = dedupePoints(points)
+	if len(points) < 3 {
+		return points
+	}
+	if tolerance <= 0 {
+		tolerance = bezierTolerance
+	}
+
+	var (
+		closed = len(points) > 2 && points[0] == points[len(points)-1]
+		n      = len(points)
+		result = []Point{points[0]}
+	)
+	if closed {
+		n-- // the repeated last point
+	}
+
+	// The neighbors of the ends are the ends themselves, or wrap around.
+	at := func(i int) [2]float64 {
+		if closed {
+			return floatPoint(points[(i+n)%n])
+		}
+		if i < 0 {
+			i = 0
+		} else if i >= n {
+			i = n - 1
+		}
+		return floatPoint(points[i])
+	}
+
+	var segments = n - 1
+	if closed {
+		segments = n
+	}
+	for i := 0; i < segments; i++ {
+		// Each span of the spline is a cubic Bezier curve with control
+		// points along the tangents at its ends.
+		var (
+			p0, p1, p2, p3 = at(i - 1), at(i), at(i + 1), at(i + 2)
+			c1             = [2]float64{p1[0] + (p2[0]-p0[0])/6, p1[1] + (p2[1]-p0[1])/6}
+			c2             = [2]float64{p2[0] - (p3[0]-p1[0])/6, p2[1] - (p3[1]-p1[1])/6}
+		)
+		flattenCubic(p1, c1, c2, p2, tolerance, func(v [2]float64) bool {
+			result = appendPoint(result, NewPoint(round(v[0]), round(v[1])))
+			return true
+		})
+	}
+	return result
+}
+
+// SmoothChaikin rounds off the corners of a polyline with Chaikin's corner
+// cutting algorithm: each pass replaces every corner with two points a
+// quarter of the way along its lines. More iterations give smoother curves,
+// and two to four are usually enough.
+//
+// The ends of an open polyline stay in place. If the last point is the same
+// as the first, the polyline is closed and all of its corners are cut.
+func SmoothChaikin(points []Point, iterations int) []Point {
+	points = dedupePoints(points)
+	if len(points) < 3 {
+		return points
+	}
+
+	var (
+		closed = len(points) > 2 && points[0] == points[len(points)-1]
+		curve  = make([][2]float64, len(points))
+	)
+	for i, pt := range points {
+		curve[i] = floatPoint(pt)
+	}
+	if closed {
+		curve = curve[:len(curve)-1]
+	}
+
+	for iter := 0; iter < iterations; iter++ {
+		var (
+			next     = make([][2]float64, 0, len(curve)*2)
+			segments = len(curve) - 1
+		)
+		if closed {
+			segments = len(curve)
+		} else {
+			next = append(next, curve[0])
+		}
+
+		for i := 0; i < segments; i++ {
+			var (
+				a = curve[i]
+				b = curve[(i+1)%len(curve)]
+			)
+			next = append(next,
+				[2]float64{a[0]*0.75 + b[0]*0.25, a[1]*0.75 + b[1]*0.25},
+				[2]float64{a[0]*0.25 + b[0]*0.75, a[1]*0.25 + b[1]*0.75},
+			)
+		}
+
+		if closed {
+			curve = next
+		} else {
+			// Keep the ends instead of the cuts next to them.
+			next[1] = curve[0]
+			next[len(next)-1] = curve[len(curve)-1]
+			curve = next[1:]
+		}
+	}
+
+	var result = make([]Point, 0, len(curve)+1)
+	for _, v := range curve {
+		result = appendPoint(result, NewPoint(round(v[0]), round(v[1])))
+	}
+	if closed {
+		result = appendPoint(result, result[0])
+	}
+	return result
+}
+
+// dedupePoints returns the points without consecutive repeats.
+func dedupePoints(points []Point) []Point {
+	var result = make([]Point, 0, len(points))
+	for _, pt := range points {
+		result = appendPoint(result, pt)
+	}
+	return result
+}
+
+// appendPoint appends a point unless it repeats the last one.
+func appendPoint(points []Point, pt Point) []Point {
+	if len(points) > 0 && points[len(points)-1] == pt {
+		return points
+	}
+	return append(points, pt)
+}
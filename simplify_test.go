@@ -0,0 +1,118 @@
+package render_test
+
+import (
+	"math"
+	"testing"
+
+	"git.kirsle.net/go/render"
+)
+
+func TestSimplifyPolyline(t *testing.T) {
+	var p = render.NewPoint
+
+	var tests = []struct {
+		Name      string
+		Points    []render.Point
+		Tolerance float64
+		Expect    []render.Point
+	}{
+		{
+			Name:   "straight line",
+			Points: []render.Point{p(0, 0), p(1, 1), p(2, 2), p(2, 2), p(5, 5)},
+			Expect: []render.Point{p(0, 0), p(5, 5)},
+		},
+		{
+			Name:      "jitter",
+			Points:    []render.Point{p(0, 0), p(3, 1), p(6, -1), p(10, 0), p(10, 10)},
+			Tolerance: 1.5,
+			Expect:    []render.Point{p(0, 0), p(10, 0), p(10, 10)},
+		},
+		{
+			Name:   "doubling back",
+			Points: []render.Point{p(0, 0), p(10, 0), p(5, 0)},
+			Expect: []render.Point{p(0, 0), p(10, 0), p(5, 0)},
+		},
+		{
+			Name:      "closed",
+			Points:    []render.Point{p(0, 0), p(5, 0), p(10, 0), p(10, 10), p(0, 10), p(0, 0)},
+			Tolerance: 1,
+			Expect:    []render.Point{p(0, 0), p(10, 0), p(10, 10), p(0, 10), p(0, 0)},
+		},
+		{
+			Name:   "too short",
+			Points: []render.Point{p(4, 4)},
+			Expect: []render.Point{p(4, 4)},
+		},
+	}
+	for _, test := range tests {
+		actual := render.SimplifyPolyline(test.Points, test.Tolerance)
+		if len(actual) != len(test.Expect) {
+			t.Errorf("%s: expected %v, got %v", test.Name, test.Expect, actual)
+			continue
+		}
+		for i := range actual {
+			if actual[i] != test.Expect[i] {
+				t.Errorf("%s: expected %v, got %v", test.Name, test.Expect, actual)
+				break
+			}
+		}
+	}
+}
+
+func TestSmoothPolyline(t *testing.T) {
+	var (
+		p      = render.NewPoint
+		zigzag = []render.Point{p(0, 0), p(20, 20), p(40, 0), p(60, 20)}
+		square = []render.Point{p(0, 0), p(40, 0), p(40, 40), p(0, 40), p(0, 0)}
+	)
+
+	// contains returns whether the curve passes by a point.
+	contains := func(curve []render.Point, pt render.Point) bool {
+		for _, c := range curve {
+			if math.Hypot(float64(c.X-pt.X), float64(c.Y-pt.Y)) <= 1 {
+				return true
+			}
+		}
+		return false
+	}
+
+	// Catmull-Rom goes through every point.
+	var curve = render.SmoothCatmullRom(zigzag, 0.5)
+	for _, pt := range zigzag {
+		if !contains(curve, pt) {
+			t.Errorf("SmoothCatmullRom: expected the curve to pass through %s", pt)
+		}
+	}
+	if curve[0] != zigzag[0] || curve[len(curve)-1] != zigzag[len(zigzag)-1] {
+		t.Errorf("SmoothCatmullRom: expected the curve to keep its ends, got %v", curve)
+	}
+	if len(curve) <= len(zigzag) {
+		t.Errorf("SmoothCatmullRom: expected more points for the curves, got %v", curve)
+	}
+
+	// A closed square becomes a rounded loop.
+	curve = render.SmoothCatmullRom(square, 0.5)
+	if curve[0] != curve[len(curve)-1] {
+		t.Errorf("SmoothCatmullRom: expected a closed curve, got %v", curve)
+	}
+	if !contains(curve, p(40, 40)) || contains(curve, p(20, 0)) {
+		t.Errorf("SmoothCatmullRom: expected the square's sides to bulge out, got %v", curve)
+	}
+
+	// Chaikin cuts the corners but keeps the ends of an open polyline.
+	curve = render.SmoothChaikin(zigzag, 3)
+	if curve[0] != zigzag[0] || curve[len(curve)-1] != zigzag[len(zigzag)-1] {
+		t.Errorf("SmoothChaikin: expected the curve to keep its ends, got %v", curve)
+	}
+	if contains(curve, p(20, 20)) || contains(curve, p(40, 0)) {
+		t.Errorf("SmoothChaikin: expected the corners to be cut, got %v", curve)
+	}
+
+	curve = render.SmoothChaikin(square, 2)
+	if curve[0] != curve[len(curve)-1] || contains(curve, p(0, 0)) {
+		t.Errorf("SmoothChaikin: expected a closed loop without corners, got %v", curve)
+	}
+	if len(curve) != 4*4+1 {
+		t.Errorf("SmoothChaikin: expected 17 points, got %d", len(curve))
+	}
+}
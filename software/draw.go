@@ -0,0 +1,359 @@
+package software
+
+import (
+	"image"
+	"math"
+
+	"git.kirsle.net/go/render"
+)
+
+// Methods here implement the drawing functions of the render.Engine
+
+// Clear the frame buffer and fill it with this color.
+//
+// Like SDL's RenderClear, the color replaces the pixels outright rather than
+// being blended over them.
+func (e *Engine) Clear(color render.Color) {
+	var (
+		r, g, b, a = premultiply(color)
+		pix        = e.image.Pix
+	)
+	for i := 0; i+3 < len(pix); i += 4 {
+		pix[i+0] = r
+		pix[i+1] = g
+		pix[i+2] = b
+		pix[i+3] = a
+	}
+}
+
+// DrawPoint puts a color at a pixel.
+func (e *Engine) DrawPoint(color render.Color, point render.Point) {
+	m := e.transform.Current()
+	if offset, ok := m.Offset(); ok {
+		e.blend(point.X+offset.X, point.Y+offset.Y, color)
+		return
+	}
+	m.Boxes(render.Rect{X: point.X, Y: point.Y, W: 1, H: 1}, func(box render.Rect) {
+		e.fillRect(box, color)
+	})
+}
+
+// DrawLine draws a line between two points.
+func (e *Engine) DrawLine(color render.Color, a, b render.Point) {
+	m := e.transform.Current()
+	offset, ok := m.Offset()
+	if !ok {
+		m.LineBoxes(a, b, func(box render.Rect) {
+			e.fillRect(box, color)
+		})
+		return
+	}
+
+	a.Add(offset)
+	b.Add(offset)
+	render.LinePoints(a, b)(func(pt render.Point) bool {
+		e.blend(pt.X, pt.Y, color)
+		return true
+	})
+}
+
+// DrawRect draws a rectangle outline.
+//
+// As with SDL, the outline covers the pixels from X to X+W-1 and from Y to
+// Y+H-1.
+func (e *Engine) DrawRect(color render.Color, rect render.Rect) {
+	if rect.W <= 0 || rect.H <= 0 {
+		return
+	}
+
+	m := e.transform.Current()
+	offset, ok := m.Offset()
+	if !ok {
+		m.OutlineBoxes(rect, func(box render.Rect) {
+			e.fillRect(box, color)
+		})
+		return
+	}
+	rect = rect.AddPoint(offset)
+
+	var (
+		x2 = rect.X + rect.W - 1
+		y2 = rect.Y + rect.H - 1
+	)
+
+	// Top and bottom edges.
+	e.hline(rect.X, x2, rect.Y, color)
+	if y2 != rect.Y {
+		e.hline(rect.X, x2, y2, color)
+	}
+
+	// Left and right edges, without the corners.
+	for y := rect.Y + 1; y < y2; y++ {
+		e.blend(rect.X, y, color)
+		if x2 != rect.X {
+			e.blend(x2, y, color)
+		}
+	}
+}
+
+// DrawBox draws a filled rectangle.
+func (e *Engine) DrawBox(color render.Color, rect render.Rect) {
+	m := e.transform.Current()
+	if offset, ok := m.Offset(); ok {
+		e.fillRect(rect.AddPoint(offset), color)
+		return
+	}
+	m.Boxes(rect, func(box render.Rect) {
+		e.fillRect(box, color)
+	})
+}
+
+// DrawLineAA draws an anti-aliased line.
+//
+// Under a transform, the endpoints are transformed and the line is drawn
+// smoothly on the screen rather than as zoomed in pixels.
+func (e *Engine) DrawLineAA(color render.Color, x1, y1, x2, y2 float64) {
+	m := e.transform.Current()
+	x1, y1 = m.Apply(x1, y1)
+	x2, y2 = m.Apply(x2, y2)
+	render.WuLinePoints(x1, y1, x2, y2)(func(pt render.Point, coverage float64) bool {
+		e.blend(pt.X, pt.Y, color.ScaleAlpha(coverage))
+		return true
+	})
+}
+
+// DrawPolyline draws connected lines with a stroke style.
+func (e *Engine) DrawPolyline(color render.Color, style render.StrokeStyle, points []render.Point) {
+	e.fillSpans(color, style.Spans(points))
+}
+
+// DrawPolygon draws the outline of a polygon.
+func (e *Engine) DrawPolygon(color render.Color, points []render.Point) {
+	e.fillSpans(color, render.PolygonOutline(points))
+}
+
+// FillPolygon draws a filled polygon.
+func (e *Engine) FillPolygon(color render.Color, points []render.Point, rule render.FillRule) {
+	e.fillSpans(color, render.PolygonSpans(points, rule))
+}
+
+// DrawEllipse draws the outline of the ellipse that fits inside the rect.
+func (e *Engine) DrawEllipse(color render.Color, rect render.Rect) {
+	e.fillSpans(color, render.EllipseOutline(rect))
+}
+
+// FillEllipse draws the filled ellipse that fits inside the rect.
+func (e *Engine) FillEllipse(color render.Color, rect render.Rect) {
+	e.fillSpans(color, render.EllipseSpans(rect))
+}
+
+// DrawCircle draws the outline of a circle around a center pixel.
+func (e *Engine) DrawCircle(color render.Color, center render.Point, radius int) {
+	e.DrawEllipse(color, render.Rect{
+		X: center.X - radius,
+		Y: center.Y - radius,
+		W: radius*2 + 1,
+		H: radius*2 + 1,
+	})
+}
+
+// DrawArc draws part of the outline of the ellipse that fits inside the rect,
+// going clockwise from the start to the end angle in radians.
+func (e *Engine) DrawArc(color render.Color, rect render.Rect, start, end float64) {
+	e.fillSpans(color, render.ArcOutline(rect, start, end))
+}
+
+// DrawQuadBezier draws a quadratic Bezier curve from A to C, bending towards
+// the control point B.
+func (e *Engine) DrawQuadBezier(color render.Color, a, b, c render.Point) {
+	render.QuadBezierPoints(a, b, c)(func(pt render.Point) bool {
+		e.DrawPoint(color, pt)
+		return true
+	})
+}
+
+// DrawCubicBezier draws a cubic Bezier curve from A to D, with control points
+// B and C.
+func (e *Engine) DrawCubicBezier(color render.Color, a, b, c, d render.Point) {
+	render.CubicBezierPoints(a, b, c, d)(func(pt render.Point) bool {
+		e.DrawPoint(color, pt)
+		return true
+	})
+}
+
+// fillSpans fills rows of pixels under the current transform.
+func (e *Engine) fillSpans(color render.Color, spans []render.Span) {
+	m := e.transform.Current()
+	for _, span := range spans {
+		m.Boxes(span.Rect(), func(box render.Rect) {
+			e.fillRect(box, color)
+		})
+	}
+}
+
+// fillRect fills a rect on the frame buffer, without transforming it.
+func (e *Engine) fillRect(rect render.Rect, color render.Color) {
+	for y := rect.Y; y < rect.Y+rect.H; y++ {
+		e.hline(rect.X, rect.X+rect.W-1, y, color)
+	}
+}
+
+// PushClip limits drawing to the inside of the rect, intersected with any
+// clip already pushed.
+func (e *Engine) PushClip(rect render.Rect) {
+	e.setClip(e.clip.Push(rect), true)
+}
+
+// PopClip restores the clip rect that was current before the last PushClip.
+func (e *Engine) PopClip() {
+	e.setClip(e.clip.Pop())
+}
+
+// PushTransform transforms the coordinates of the drawing functions by the
+// matrix, combined with any transform already pushed.
+func (e *Engine) PushTransform(m render.Matrix) {
+	e.transform.Push(m)
+}
+
+// PopTransform restores the transform that was current before the last
+// PushTransform.
+func (e *Engine) PopTransform() {
+	e.transform.Pop()
+}
+
+// SetBlendMode sets how the drawing functions mix colors with the pixels of
+// the frame buffer.
+func (e *Engine) SetBlendMode(mode render.BlendMode) {
+	e.blendMode = mode
+}
+
+// SetOpacity scales the alpha of everything drawn, from 0 to 1.
+func (e *Engine) SetOpacity(opacity float64) {
+	e.opacity = uint8(math.Round(math.Max(0, math.Min(1, opacity)) * 0xff))
+}
+
+// setClip updates the drawable bounds for the current clip rect.
+func (e *Engine) setClip(rect render.Rect, enabled bool) {
+	e.bounds = e.image.Rect
+	if enabled {
+		e.bounds = e.bounds.Intersect(image.Rect(rect.X, rect.Y, rect.X+rect.W, rect.Y+rect.H))
+	}
+}
+
+// hline blends a horizontal run of pixels from x1 to x2 inclusive.
+func (e *Engine) hline(x1, x2, y int, color render.Color) {
+	for x := x1; x <= x2; x++ {
+		e.blend(x, y, color)
+	}
+}
+
+// blend draws a straight (non-premultiplied) color over a pixel.
+func (e *Engine) blend(x, y int, color render.Color) {
+	if color.Alpha == 0 && e.blendMode != render.BlendNone {
+		return
+	}
+	r, g, b, a := premultiply(color)
+	e.blendPremul(x, y, r, g, b, a)
+}
+
+// blendPremul draws an alpha-premultiplied color over a pixel with the
+// current opacity and blend mode, with the formulas documented on
+// render.BlendMode.
+func (e *Engine) blendPremul(x, y int, r, g, b, a uint8) {
+	if x < e.bounds.Min.X || x >= e.bounds.Max.X ||
+		y < e.bounds.Min.Y || y >= e.bounds.Max.Y {
+		return
+	}
+
+	if e.opacity != 0xff {
+		var opacity = uint32(e.opacity)
+		r = div255(uint32(r) * opacity)
+		g = div255(uint32(g) * opacity)
+		b = div255(uint32(b) * opacity)
+		a = div255(uint32(a) * opacity)
+	}
+
+	var (
+		i   = e.image.PixOffset(x, y)
+		pix = e.image.Pix[i : i+4 : i+4]
+	)
+
+	switch e.blendMode {
+	case render.BlendNone:
+		pix[0] = r
+		pix[1] = g
+		pix[2] = b
+		pix[3] = a
+	case render.BlendAdditive:
+		// The color is already weighted by its alpha. SDL keeps the alpha of
+		// the pixel, but adding it too keeps the premultiplied frame buffer
+		// valid where it's transparent, and is the same where it's opaque.
+		pix[0] = add8(pix[0], r)
+		pix[1] = add8(pix[1], g)
+		pix[2] = add8(pix[2], b)
+		pix[3] = add8(pix[3], a)
+	case render.BlendModulate:
+		// Multiply by the color without its alpha; the alpha is kept.
+		if a == 0 {
+			return
+		}
+		var alpha = uint32(a)
+		pix[0] = div255(uint32(pix[0]) * minUint32(uint32(r)*0xff/alpha, 0xff))
+		pix[1] = div255(uint32(pix[1]) * minUint32(uint32(g)*0xff/alpha, 0xff))
+		pix[2] = div255(uint32(pix[2]) * minUint32(uint32(b)*0xff/alpha, 0xff))
+	case render.BlendMultiply:
+		// The pixel times the color, plus the pixel showing through where
+		// the color is translucent; the alpha is kept.
+		var inv = 0xff - uint32(a)
+		pix[0] = div255(uint32(pix[0]) * (uint32(r) + inv))
+		pix[1] = div255(uint32(pix[1]) * (uint32(g) + inv))
+		pix[2] = div255(uint32(pix[2]) * (uint32(b) + inv))
+	default:
+		// The Porter-Duff "source over" operator, like SDL's BLENDMODE_BLEND.
+		if a == 0xff {
+			pix[0] = r
+			pix[1] = g
+			pix[2] = b
+			pix[3] = a
+			return
+		}
+
+		var inv = 0xff - uint32(a)
+		pix[0] = r + div255(uint32(pix[0])*inv)
+		pix[1] = g + div255(uint32(pix[1])*inv)
+		pix[2] = b + div255(uint32(pix[2])*inv)
+		pix[3] = a + div255(uint32(pix[3])*inv)
+	}
+}
+
+// premultiply returns the color's channels multiplied by its alpha, which is
+// how the *image.RGBA frame buffer stores them.
+func premultiply(c render.Color) (r, g, b, a uint8) {
+	var alpha = uint32(c.Alpha)
+	return div255(uint32(c.Red) * alpha),
+		div255(uint32(c.Green) * alpha),
+		div255(uint32(c.Blue) * alpha),
+		c.Alpha
+}
+
+// div255 divides by 255 with rounding, for values up to 255*255.
+func div255(v uint32) uint8 {
+	v += 128
+	return uint8((v + v>>8) >> 8)
+}
+
+// add8 adds two channels, saturating at 255.
+func add8(a, b uint8) uint8 {
+	if v := uint32(a) + uint32(b); v < 0xff {
+		return uint8(v)
+	}
+	return 0xff
+}
+
+// minUint32 returns the smaller of two numbers.
+func minUint32(a, b uint32) uint32 {
+	if a < b {
+		return a
+	}
+	return b
+}
@@ -0,0 +1,135 @@
+// Package software provides a pure Go rendering engine that draws into an
+// in-memory image, with no cgo, window or GPU required.
+//
+// It is useful for headless programs and continuous integration servers that
+// want to render the same scenes as the SDL2 or HTML Canvas engines and save
+// the results as PNG images.
+package software
+
+import (
+	"image"
+	"image/png"
+	"io"
+	"os"
+	"sync"
+	"time"
+
+	"git.kirsle.net/go/render"
+	"git.kirsle.net/go/render/event"
+)
+
+// Engine implements a software rendering engine that draws into an
+// *image.RGBA frame buffer.
+type Engine struct {
+	// Configurable fields.
+	title  string
+	width  int
+	height int
+
+	// Private fields.
+	startTime time.Time
+	events    *event.State
+	image     *image.RGBA
+	textures  map[string]*Texture // cached textures
+	textureMu sync.RWMutex
+
+	// Drawing state.
+	clip      render.ClipStack
+	transform render.TransformStack
+	bounds    image.Rectangle // the drawable area: frame buffer and clip rect
+	blendMode render.BlendMode
+	opacity   uint8 // multiplies the alpha of everything drawn
+}
+
+// New creates the software Engine with a frame buffer of the given size.
+func New(width, height int) *Engine {
+	var frame = image.NewRGBA(image.Rect(0, 0, width, height))
+	return &Engine{
+		width:     width,
+		height:    height,
+		startTime: time.Now(),
+		events:    event.NewState(),
+		image:     frame,
+		bounds:    frame.Rect,
+		opacity:   0xff,
+		textures:  map[string]*Texture{},
+	}
+}
+
+// Setup the engine. The frame buffer is already allocated by New, so this
+// does nothing.
+func (e *Engine) Setup() error {
+	return nil
+}
+
+// Teardown tasks when exiting the program.
+func (e *Engine) Teardown() {
+	e.FreeTextures()
+}
+
+// SetTitle sets the window title. There is no window, but the title is
+// remembered and can be read back by the Title function.
+func (e *Engine) SetTitle(title string) {
+	e.title = title
+}
+
+// Title returns the last title set by SetTitle.
+func (e *Engine) Title() string {
+	return e.title
+}
+
+// Poll for events. The software engine has no input devices, so the returned
+// event.State only changes when the caller modifies it.
+func (e *Engine) Poll() (*event.State, error) {
+	return e.events, nil
+}
+
+// GetTicks returns the number of milliseconds since the engine started.
+func (e *Engine) GetTicks() uint32 {
+	return uint32(time.Since(e.startTime) / time.Millisecond)
+}
+
+// WindowSize returns the size of the frame buffer.
+func (e *Engine) WindowSize() (w, h int) {
+	return e.width, e.height
+}
+
+// Present the current frame. Drawing goes straight to the frame buffer, so
+// this does nothing.
+func (e *Engine) Present() error {
+	return nil
+}
+
+// Delay for a moment.
+func (e *Engine) Delay(delay uint32) {
+	time.Sleep(time.Duration(delay) * time.Millisecond)
+}
+
+// Loop is the main loop.
+func (e *Engine) Loop() error {
+	return nil
+}
+
+// Image returns the frame buffer the engine draws into.
+func (e *Engine) Image() *image.RGBA {
+	return e.image
+}
+
+// EncodePNG writes the current frame buffer as a PNG image.
+func (e *Engine) EncodePNG(w io.Writer) error {
+	return png.Encode(w, e.image)
+}
+
+// SavePNG writes the current frame buffer to a PNG file on disk.
+func (e *Engine) SavePNG(filename string) error {
+	fh, err := os.Create(filename)
+	if err != nil {
+		return err
+	}
+
+	if err := e.EncodePNG(fh); err != nil {
+		fh.Close()
+		return err
+	}
+	return fh.Close()
+}
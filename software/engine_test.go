@@ -0,0 +1,345 @@
+package software_test
+
+import (
+	"bytes"
+	"image"
+	"image/png"
+	"math"
+	"testing"
+
+	"git.kirsle.net/go/render"
+	"git.kirsle.net/go/render/software"
+	"golang.org/x/image/font/gofont/goregular"
+)
+
+// The software Engine must satisfy the render.Engine interface.
+var _ render.Engine = &software.Engine{}
+
+// pixel reads back a pixel from the frame buffer as a render.Color.
+func pixel(e *software.Engine, x, y int) render.Color {
+	return render.FromColor(e.Image().At(x, y))
+}
+
+func TestDrawPrimitives(t *testing.T) {
+	e := software.New(32, 32)
+	e.Clear(render.White)
+
+	e.DrawBox(render.Red, render.Rect{X: 2, Y: 2, W: 4, H: 4})
+	e.DrawRect(render.Blue, render.Rect{X: 10, Y: 10, W: 5, H: 5})
+	e.DrawLine(render.Green, render.NewPoint(0, 20), render.NewPoint(31, 20))
+	e.DrawPoint(render.Black, render.NewPoint(30, 30))
+
+	var tests = []struct {
+		X, Y   int
+		Expect render.Color
+	}{
+		{0, 0, render.White},
+		{2, 2, render.Red},
+		{5, 5, render.Red},
+		{6, 6, render.White},
+		{10, 10, render.Blue},
+		{14, 14, render.Blue},
+		{12, 12, render.White}, // inside the outline
+		{15, 15, render.White}, // outline stops at X+W-1
+		{0, 20, render.Green},
+		{31, 20, render.Green},
+		{30, 30, render.Black},
+	}
+	for _, test := range tests {
+		if actual := pixel(e, test.X, test.Y); actual != test.Expect {
+			t.Errorf("pixel at %d,%d: expected %s, got %s",
+				test.X, test.Y, test.Expect, actual,
+			)
+		}
+	}
+}
+
+func TestAlphaBlending(t *testing.T) {
+	e := software.New(4, 4)
+	e.Clear(render.White)
+	e.DrawPoint(render.RGBA(0, 0, 0, 128), render.NewPoint(1, 1))
+
+	actual := pixel(e, 1, 1)
+	if actual.Alpha != 255 || actual.Red < 126 || actual.Red > 128 {
+		t.Errorf("half transparent black over white: expected grey, got %s", actual)
+	}
+}
+
+func TestBlendModes(t *testing.T) {
+	var (
+		background = render.RGBA(100, 150, 200, 255)
+		img        = image.NewRGBA(image.Rect(0, 0, 1, 1))
+	)
+	img.Set(0, 0, render.RGBA(100, 0, 0, 255).ToColor())
+
+	var tests = []struct {
+		Name    string
+		Mode    render.BlendMode
+		Opacity float64
+		Color   render.Color
+		Copy    bool // copy the texture instead of drawing the color
+		Expect  render.Color
+	}{
+		{"alpha", render.BlendAlpha, 1, render.RGBA(0, 0, 0, 128), false, render.RGBA(50, 75, 100, 255)},
+		{"opacity", render.BlendAlpha, 0.5, render.Black, false, render.RGBA(50, 75, 100, 255)},
+		{"none", render.BlendNone, 1, render.RGBA(10, 20, 30, 0), false, render.Invisible},
+		{"additive", render.BlendAdditive, 1, render.RGBA(100, 100, 100, 255), false, render.RGBA(200, 250, 255, 255)},
+		{"additive texture", render.BlendAdditive, 1, render.Invisible, true, render.RGBA(200, 150, 200, 255)},
+		{"additive opacity", render.BlendAdditive, 0.5, render.Invisible, true, render.RGBA(150, 150, 200, 255)},
+		{"modulate", render.BlendModulate, 1, render.RGBA(128, 255, 0, 255), false, render.RGBA(50, 150, 0, 255)},
+		{"multiply translucent", render.BlendMultiply, 1, render.RGBA(128, 255, 0, 128), false, render.RGBA(75, 150, 100, 255)},
+		{"multiply translucent white", render.BlendMultiply, 1, render.RGBA(255, 255, 255, 128), false, render.RGBA(100, 150, 200, 255)},
+		{"multiply", render.BlendMultiply, 1, render.RGBA(0, 0, 0, 128), false, render.RGBA(50, 75, 100, 255)},
+		{"multiply opaque", render.BlendMultiply, 1, render.RGBA(128, 255, 0, 255), false, render.RGBA(50, 150, 0, 255)},
+	}
+	for _, test := range tests {
+		e := software.New(4, 4)
+		tex, _ := e.StoreTexture("red", img)
+		e.Clear(background)
+		e.SetBlendMode(test.Mode)
+		e.SetOpacity(test.Opacity)
+		if test.Copy {
+			e.Copy(tex, tex.Size(), render.Rect{X: 1, Y: 1, W: 1, H: 1})
+		} else {
+			e.DrawPoint(test.Color, render.NewPoint(1, 1))
+		}
+
+		if actual := pixel(e, 1, 1); actual != test.Expect {
+			t.Errorf("%s: expected %s, got %s", test.Name, test.Expect, actual)
+		}
+		if actual := pixel(e, 0, 0); actual != background {
+			t.Errorf("%s: expected the rest of the frame untouched, got %s", test.Name, actual)
+		}
+
+		// Clear replaces the pixels whatever the blend mode.
+		e.Clear(render.White)
+		if actual := pixel(e, 1, 1); actual != render.White {
+			t.Errorf("%s: expected Clear to ignore the blend mode, got %s", test.Name, actual)
+		}
+	}
+}
+
+func TestDrawInvisibleAfterColor(t *testing.T) {
+	e := software.New(4, 4)
+	e.Clear(render.White)
+	e.DrawPoint(render.Red, render.NewPoint(0, 0))
+	e.SetOpacity(0)
+	e.DrawPoint(render.Black, render.NewPoint(1, 1))
+
+	if actual := pixel(e, 1, 1); actual != render.White {
+		t.Errorf("expected the pixel to stay %s, got %s", render.White, actual)
+	}
+}
+
+func TestDrawLineAA(t *testing.T) {
+	e := software.New(8, 8)
+	e.Clear(render.White)
+
+	// Halfway between two rows: both get half of the ink.
+	e.DrawLineAA(render.Black, 1, 2.5, 6, 2.5)
+	for _, y := range []int{2, 3} {
+		actual := pixel(e, 4, y)
+		if actual.Red < 120 || actual.Red > 135 {
+			t.Errorf("pixel at 4,%d: expected grey, got %s", y, actual)
+		}
+	}
+	if actual := pixel(e, 4, 1); actual != render.White {
+		t.Errorf("pixel at 4,1: expected white, got %s", actual)
+	}
+}
+
+func TestClip(t *testing.T) {
+	e := software.New(32, 32)
+	e.Clear(render.White)
+
+	e.PushClip(render.Rect{X: 4, Y: 4, W: 20, H: 20})
+	e.PushClip(render.Rect{X: 8, Y: 0, W: 32, H: 12}) // intersects to 8,4 16x8
+	e.DrawBox(render.Red, render.Rect{X: 0, Y: 0, W: 32, H: 32})
+	e.PopClip()
+	e.DrawLine(render.Blue, render.NewPoint(0, 16), render.NewPoint(31, 16))
+	e.PopClip()
+	e.DrawPoint(render.Black, render.NewPoint(0, 0))
+
+	var tests = []struct {
+		X, Y   int
+		Expect render.Color
+	}{
+		{8, 4, render.Red},
+		{23, 11, render.Red},
+		{7, 4, render.White},
+		{24, 11, render.White},
+		{8, 12, render.White},
+		{3, 16, render.White},
+		{4, 16, render.Blue},
+		{23, 16, render.Blue},
+		{24, 16, render.White},
+		{0, 0, render.Black},
+	}
+	for _, test := range tests {
+		if actual := pixel(e, test.X, test.Y); actual != test.Expect {
+			t.Errorf("pixel at %d,%d: expected %s, got %s",
+				test.X, test.Y, test.Expect, actual,
+			)
+		}
+	}
+
+	// Clear ignores the clip rect, like SDL.
+	e.PushClip(render.Rect{X: 0, Y: 0, W: 1, H: 1})
+	e.Clear(render.Green)
+	e.PopClip()
+	if actual := pixel(e, 31, 31); actual != render.Green {
+		t.Errorf("Clear: expected the whole frame to be cleared, got %s at 31,31", actual)
+	}
+}
+
+func TestTransform(t *testing.T) {
+	e := software.New(32, 32)
+	e.Clear(render.White)
+
+	// Zoomed in 4x and scrolled: a point becomes a 4x4 box.
+	e.PushTransform(render.Translation(8, 8))
+	e.PushTransform(render.Scaling(4, 4))
+	e.DrawPoint(render.Red, render.NewPoint(1, 1))
+	e.PopTransform()
+	e.DrawBox(render.Blue, render.Rect{X: 0, Y: 0, W: 2, H: 2})
+	e.PopTransform()
+
+	// Rotated by 90 degrees: a horizontal line becomes vertical.
+	e.PushTransform(render.Translation(30, 0).Rotate(math.Pi / 2))
+	e.DrawLine(render.Green, render.NewPoint(0, 0), render.NewPoint(9, 0))
+	e.PopTransform()
+
+	var tests = []struct {
+		X, Y   int
+		Expect render.Color
+	}{
+		{8, 8, render.Blue},
+		{9, 9, render.Blue},
+		{10, 10, render.White},
+		{12, 12, render.Red},
+		{15, 15, render.Red},
+		{16, 16, render.White},
+		{29, 0, render.Green},
+		{29, 9, render.Green},
+		{29, 10, render.White},
+		{25, 0, render.White},
+	}
+	for _, test := range tests {
+		if actual := pixel(e, test.X, test.Y); actual != test.Expect {
+			t.Errorf("pixel at %d,%d: expected %s, got %s",
+				test.X, test.Y, test.Expect, actual,
+			)
+		}
+	}
+}
+
+func TestCopyTexture(t *testing.T) {
+	var img = image.NewRGBA(image.Rect(0, 0, 2, 2))
+	img.Set(0, 0, render.Red.ToColor())
+	img.Set(1, 0, render.Green.ToColor())
+	img.Set(0, 1, render.Blue.ToColor())
+
+	e := software.New(8, 8)
+	e.Clear(render.White)
+
+	tex, err := e.StoreTexture("test.png", img)
+	if err != nil {
+		t.Fatalf("StoreTexture: %s", err)
+	}
+	if loaded, err := e.LoadTexture("test.png"); err != nil || loaded != tex {
+		t.Errorf("LoadTexture: expected the stored texture back, got %v (err: %v)", loaded, err)
+	}
+
+	// Copy scaled up 2x.
+	e.Copy(tex, tex.Size(), render.Rect{X: 4, Y: 4, W: 4, H: 4})
+	var tests = []struct {
+		X, Y   int
+		Expect render.Color
+	}{
+		{4, 4, render.Red},
+		{5, 5, render.Red},
+		{6, 4, render.Green},
+		{4, 6, render.Blue},
+		{7, 7, render.White}, // transparent texture pixel
+		{3, 3, render.White},
+	}
+	for _, test := range tests {
+		if actual := pixel(e, test.X, test.Y); actual != test.Expect {
+			t.Errorf("pixel at %d,%d: expected %s, got %s",
+				test.X, test.Y, test.Expect, actual,
+			)
+		}
+	}
+
+	if n := e.FreeTextures(); n != 1 {
+		t.Errorf("FreeTextures: expected 1 freed texture, got %d", n)
+	}
+}
+
+func TestDrawText(t *testing.T) {
+	software.InstallFont("goregular.ttf", goregular.TTF)
+
+	var text = render.Text{
+		Text:         "Hello",
+		Size:         16,
+		Color:        render.Black,
+		FontFilename: "goregular.ttf",
+	}
+
+	e := software.New(100, 40)
+	e.Clear(render.White)
+
+	rect, err := e.ComputeTextRect(text)
+	if err != nil {
+		t.Fatalf("ComputeTextRect: %s", err)
+	}
+	if rect.W <= 0 || rect.H < text.Size {
+		t.Errorf("ComputeTextRect: unexpected size %s", rect)
+	}
+
+	if err := e.DrawText(text, render.NewPoint(4, 4)); err != nil {
+		t.Fatalf("DrawText: %s", err)
+	}
+
+	// Some ink must have landed inside the text rect and none outside of it.
+	var inked bool
+	for y := 0; y < 40; y++ {
+		for x := 0; x < 100; x++ {
+			if pixel(e, x, y) == render.White {
+				continue
+			}
+			if !render.NewPoint(x, y).Inside(rect.AddPoint(render.NewPoint(4, 4))) {
+				t.Fatalf("DrawText: ink at %d,%d outside of the text rect %s", x, y, rect)
+			}
+			inked = true
+		}
+	}
+	if !inked {
+		t.Errorf("DrawText: no pixels were drawn")
+	}
+
+	if _, err := e.ComputeTextRect(render.Text{FontFilename: "missing.ttf"}); err == nil {
+		t.Errorf("ComputeTextRect: expected an error for a missing font file")
+	}
+}
+
+func TestEncodePNG(t *testing.T) {
+	e := software.New(3, 2)
+	e.Clear(render.SkyBlue)
+
+	var buf bytes.Buffer
+	if err := e.EncodePNG(&buf); err != nil {
+		t.Fatalf("EncodePNG: %s", err)
+	}
+
+	img, err := png.Decode(&buf)
+	if err != nil {
+		t.Fatalf("png.Decode: %s", err)
+	}
+	if size := img.Bounds().Size(); size.X != 3 || size.Y != 2 {
+		t.Errorf("decoded PNG: expected 3x2, got %dx%d", size.X, size.Y)
+	}
+	if actual := render.FromColor(img.At(2, 1)); actual != render.SkyBlue {
+		t.Errorf("decoded PNG: expected %s, got %s", render.SkyBlue, actual)
+	}
+}
@@ -0,0 +1,300 @@
+package software
+
+// Text rendering functions using pure Go TrueType fonts.
+
+import (
+	"fmt"
+	"image"
+	"image/draw"
+	"io/ioutil"
+	"sync"
+
+	"git.kirsle.net/go/render"
+	"golang.org/x/image/font"
+	"golang.org/x/image/font/sfnt"
+	"golang.org/x/image/math/fixed"
+	"golang.org/x/image/vector"
+)
+
+// DefaultFontFilename is the font used when a render.Text doesn't name one.
+var DefaultFontFilename = "DejaVuSans.ttf"
+
+var (
+	fonts         = map[string]*sfnt.Font{} // parsed fonts by filename
+	installedFont = map[string][]byte{}     // installed font files' binary handles
+	fontsMu       sync.RWMutex
+)
+
+// InstallFont preloads the font cache using TTF binary data in memory.
+func InstallFont(filename string, binary []byte) {
+	fontsMu.Lock()
+	installedFont[filename] = binary
+	delete(fonts, filename)
+	fontsMu.Unlock()
+}
+
+// LoadFont loads and caches a font by filename.
+//
+// Fonts installed with InstallFont are used first, otherwise the filename is
+// read from disk.
+func LoadFont(filename string) (*sfnt.Font, error) {
+	if filename == "" {
+		filename = DefaultFontFilename
+	}
+
+	// Cached font available?
+	fontsMu.RLock()
+	face, ok := fonts[filename]
+	fontsMu.RUnlock()
+	if ok {
+		return face, nil
+	}
+
+	fontsMu.Lock()
+	defer fontsMu.Unlock()
+
+	binary, ok := installedFont[filename]
+	if !ok {
+		var err error
+		if binary, err = ioutil.ReadFile(filename); err != nil {
+			return nil, fmt.Errorf("LoadFont(%s): %s", filename, err)
+		}
+	}
+
+	face, err := sfnt.Parse(binary)
+	if err != nil {
+		return nil, fmt.Errorf("LoadFont(%s): %s", filename, err)
+	}
+
+	// Cache this font.
+	fonts[filename] = face
+
+	return face, nil
+}
+
+// ComputeTextRect computes and returns a Rect for how large the text would
+// appear if rendered.
+func (e *Engine) ComputeTextRect(text render.Text) (render.Rect, error) {
+	var rect render.Rect
+
+	face, err := LoadFont(text.FontFilename)
+	if err != nil {
+		return rect, err
+	}
+
+	width, metrics, err := measureText(face, text)
+	if err != nil {
+		return rect, err
+	}
+
+	rect.W = width
+	rect.H = (metrics.Ascent + metrics.Descent).Ceil()
+	return rect, nil
+}
+
+// DrawText draws text on the frame buffer.
+func (e *Engine) DrawText(text render.Text, point render.Point) error {
+	face, err := LoadFont(text.FontFilename)
+	if err != nil {
+		return err
+	}
+
+	// Without rotation or scaling the text is rasterized once and moved
+	// around for its stroke and shadow; otherwise every copy is rasterized
+	// under the transform.
+	var (
+		m              = e.transform.Current().Translate(float64(point.X), float64(point.Y))
+		offset, simple = m.Offset()
+		mask           *image.Alpha
+	)
+	if simple {
+		if mask, err = rasterizeText(face, text, render.Translation(float64(offset.X), float64(offset.Y))); err != nil {
+			return err
+		}
+	}
+
+	// After a pass fails, the rest are skipped so its error is returned.
+	write := func(dx, dy int, color render.Color) {
+		if err != nil {
+			return
+		}
+		if simple {
+			e.drawMask(mask, dx, dy, color)
+			return
+		}
+
+		var transformed *image.Alpha
+		if transformed, err = rasterizeText(face, text, m.Translate(float64(dx), float64(dy))); err != nil {
+			return
+		}
+		e.drawMask(transformed, 0, 0, color)
+	}
+
+	// Does the text have a stroke around it?
+	if text.Stroke != render.Invisible {
+		write(-1, -1, text.Stroke)
+		write(-1, 0, text.Stroke)
+		write(-1, 1, text.Stroke)
+		write(1, -1, text.Stroke)
+		write(1, 0, text.Stroke)
+		write(1, 1, text.Stroke)
+		write(0, -1, text.Stroke)
+		write(0, 1, text.Stroke)
+	}
+
+	// Does it have a drop shadow?
+	if text.Shadow != render.Invisible {
+		write(1, 1, text.Shadow)
+	}
+
+	// Draw the text itself.
+	write(0, 0, text.Color)
+
+	return err
+}
+
+// drawMask blends a color onto the frame buffer through an alpha mask. The
+// mask's bounds are in frame buffer coordinates, moved by dx,dy.
+func (e *Engine) drawMask(mask *image.Alpha, dx, dy int, color render.Color) {
+	var bounds = mask.Bounds()
+	for my := bounds.Min.Y; my < bounds.Max.Y; my++ {
+		for mx := bounds.Min.X; mx < bounds.Max.X; mx++ {
+			coverage := mask.Pix[mask.PixOffset(mx, my)]
+			if coverage == 0 {
+				continue
+			}
+
+			c := color
+			c.Alpha = div255(uint32(color.Alpha) * uint32(coverage))
+			e.blend(mx+dx, my+dy, c)
+		}
+	}
+}
+
+// measureText returns the advance width of the text in pixels and the font
+// metrics at its size.
+func measureText(face *sfnt.Font, text render.Text) (int, font.Metrics, error) {
+	var (
+		buf   sfnt.Buffer
+		ppem  = fixed.I(text.Size)
+		width fixed.Int26_6
+		prev  sfnt.GlyphIndex
+	)
+
+	metrics, err := face.Metrics(&buf, ppem, font.HintingNone)
+	if err != nil {
+		return 0, metrics, err
+	}
+
+	for i, r := range text.Text {
+		idx, err := face.GlyphIndex(&buf, r)
+		if err != nil {
+			return 0, metrics, err
+		}
+
+		if i > 0 {
+			if kern, err := face.Kern(&buf, prev, idx, ppem, font.HintingNone); err == nil {
+				width += kern
+			}
+		}
+
+		advance, err := face.GlyphAdvance(&buf, idx, ppem, font.HintingNone)
+		if err != nil {
+			return 0, metrics, err
+		}
+		width += advance
+		prev = idx
+	}
+
+	return width.Ceil(), metrics, nil
+}
+
+// rasterizeText renders the text into an alpha mask. The matrix maps the
+// text's ComputeTextRect onto the frame buffer, and the mask covers the
+// area where it lands.
+func rasterizeText(face *sfnt.Font, text render.Text, m render.Matrix) (*image.Alpha, error) {
+	width, metrics, err := measureText(face, text)
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		buf    sfnt.Buffer
+		ppem   = fixed.I(text.Size)
+		height = (metrics.Ascent + metrics.Descent).Ceil()
+		box    = m.TransformRect(render.Rect{W: width, H: height})
+		dot    = fixed.Point26_6{Y: metrics.Ascent}
+		prev   sfnt.GlyphIndex
+	)
+
+	// Antialiased edges of rotated glyphs may spill past the rounded box.
+	if _, ok := m.Offset(); !ok {
+		box = render.Rect{X: box.X - 1, Y: box.Y - 1, W: box.W + 2, H: box.H + 2}
+	}
+
+	var mask = image.NewAlpha(image.Rect(box.X, box.Y, box.X+box.W, box.Y+box.H))
+	if width <= 0 || height <= 0 {
+		return mask, nil
+	}
+
+	// Move the vector pen relative to the glyph's origin on the baseline,
+	// then into the mask's coordinates.
+	point := func(p fixed.Point26_6) (float32, float32) {
+		x, y := m.Apply(float64(dot.X+p.X)/64, float64(dot.Y+p.Y)/64)
+		return float32(x - float64(box.X)), float32(y - float64(box.Y))
+	}
+
+	raster := vector.NewRasterizer(box.W, box.H)
+	raster.DrawOp = draw.Src
+	for i, r := range text.Text {
+		idx, err := face.GlyphIndex(&buf, r)
+		if err != nil {
+			return nil, err
+		}
+
+		if i > 0 {
+			if kern, err := face.Kern(&buf, prev, idx, ppem, font.HintingNone); err == nil {
+				dot.X += kern
+			}
+		}
+
+		segments, err := face.LoadGlyph(&buf, idx, ppem, nil)
+		if err != nil {
+			return nil, err
+		}
+
+		for j, seg := range segments {
+			switch seg.Op {
+			case sfnt.SegmentOpMoveTo:
+				if j > 0 {
+					raster.ClosePath()
+				}
+				raster.MoveTo(point(seg.Args[0]))
+			case sfnt.SegmentOpLineTo:
+				raster.LineTo(point(seg.Args[0]))
+			case sfnt.SegmentOpQuadTo:
+				bx, by := point(seg.Args[0])
+				cx, cy := point(seg.Args[1])
+				raster.QuadTo(bx, by, cx, cy)
+			case sfnt.SegmentOpCubeTo:
+				bx, by := point(seg.Args[0])
+				cx, cy := point(seg.Args[1])
+				dx, dy := point(seg.Args[2])
+				raster.CubeTo(bx, by, cx, cy, dx, dy)
+			}
+		}
+		if len(segments) > 0 {
+			raster.ClosePath()
+		}
+
+		advance, err := face.GlyphAdvance(&buf, idx, ppem, font.HintingNone)
+		if err != nil {
+			return nil, err
+		}
+		dot.X += advance
+		prev = idx
+	}
+
+	raster.Draw(mask, mask.Bounds(), image.Opaque, image.Point{})
+	return mask, nil
+}
@@ -0,0 +1,162 @@
+package software
+
+import (
+	"fmt"
+	"image"
+	"image/draw"
+
+	"git.kirsle.net/go/render"
+)
+
+// Texture holds a cached image in the engine's native pixel format.
+type Texture struct {
+	engine *Engine     // backref to free them up thoroughly
+	rgba   *image.RGBA // pixels ready to be copied, origin at 0,0
+	image  image.Image // original Go image
+	width  int
+	height int
+}
+
+// StoreTexture caches a texture from a Go image.
+func (e *Engine) StoreTexture(name string, img image.Image) (render.Texturer, error) {
+	var (
+		bounds = img.Bounds()
+		rgba   = image.NewRGBA(image.Rect(0, 0, bounds.Dx(), bounds.Dy()))
+	)
+	draw.Draw(rgba, rgba.Bounds(), img, bounds.Min, draw.Src)
+
+	tex := &Texture{
+		engine: e,
+		rgba:   rgba,
+		image:  img,
+		width:  bounds.Dx(),
+		height: bounds.Dy(),
+	}
+
+	e.textureMu.Lock()
+	e.textures[name] = tex
+	e.textureMu.Unlock()
+
+	return tex, nil
+}
+
+// LoadTexture recalls a cached texture image.
+func (e *Engine) LoadTexture(name string) (render.Texturer, error) {
+	e.textureMu.RLock()
+	defer e.textureMu.RUnlock()
+
+	if tex, ok := e.textures[name]; ok {
+		return tex, nil
+	}
+	return nil, fmt.Errorf("LoadTexture(%s): not found in texture cache", name)
+}
+
+// Copy a texture onto the frame buffer, scaling the src rect of the texture
+// to fill the dst rect on screen.
+func (e *Engine) Copy(t render.Texturer, src, dst render.Rect) {
+	tex, ok := t.(*Texture)
+	if !ok || src.W <= 0 || src.H <= 0 || dst.W <= 0 || dst.H <= 0 {
+		return
+	}
+
+	m := e.transform.Current()
+	offset, ok := m.Offset()
+	if !ok {
+		e.copyTransformed(tex, src, dst, m)
+		return
+	}
+	dst = dst.AddPoint(offset)
+
+	// Nearest neighbor scaling from source to destination pixels.
+	for dy := 0; dy < dst.H; dy++ {
+		sy := src.Y + dy*src.H/dst.H
+		for dx := 0; dx < dst.W; dx++ {
+			sx := src.X + dx*src.W/dst.W
+			e.copyPixel(tex, sx, sy, dst.X+dx, dst.Y+dy)
+		}
+	}
+}
+
+// copyTransformed copies the src rect of a texture to the dst rect under a
+// matrix. Each screen pixel the transformed dst rect covers is mapped back
+// to its nearest texture pixel.
+func (e *Engine) copyTransformed(tex *Texture, src, dst render.Rect, m render.Matrix) {
+	inverse, ok := m.Invert()
+	if !ok {
+		return
+	}
+
+	var (
+		box    = m.TransformRect(dst)
+		bounds = image.Rect(box.X, box.Y, box.X+box.W, box.Y+box.H).Intersect(e.bounds)
+	)
+	for y := bounds.Min.Y; y < bounds.Max.Y; y++ {
+		for x := bounds.Min.X; x < bounds.Max.X; x++ {
+			// Sample at the center of the screen pixel.
+			wx, wy := inverse.Apply(float64(x)+0.5, float64(y)+0.5)
+			wx -= float64(dst.X)
+			wy -= float64(dst.Y)
+			if wx < 0 || wy < 0 || wx >= float64(dst.W) || wy >= float64(dst.H) {
+				continue
+			}
+
+			e.copyPixel(tex,
+				src.X+int(wx*float64(src.W)/float64(dst.W)),
+				src.Y+int(wy*float64(src.H)/float64(dst.H)),
+				x, y,
+			)
+		}
+	}
+}
+
+// copyPixel blends the texture pixel at sx,sy onto the frame buffer at x,y.
+func (e *Engine) copyPixel(tex *Texture, sx, sy, x, y int) {
+	if !(image.Point{X: sx, Y: sy}).In(tex.rgba.Rect) {
+		return
+	}
+
+	var (
+		i   = tex.rgba.PixOffset(sx, sy)
+		pix = tex.rgba.Pix[i : i+4 : i+4]
+	)
+	if pix[3] == 0 && e.blendMode != render.BlendNone {
+		return
+	}
+	e.blendPremul(x, y, pix[0], pix[1], pix[2], pix[3])
+}
+
+// FreeTextures flushes the texture cache.
+func (e *Engine) FreeTextures() int {
+	e.textureMu.Lock()
+	defer e.textureMu.Unlock()
+
+	var num = len(e.textures)
+	for name := range e.textures {
+		delete(e.textures, name)
+	}
+	return num
+}
+
+// Size returns the dimensions of the texture.
+func (t *Texture) Size() render.Rect {
+	return render.NewRect(t.width, t.height)
+}
+
+// Image returns the underlying Go image.Image.
+func (t *Texture) Image() image.Image {
+	return t.image
+}
+
+// Free the texture and remove it from the engine's cache.
+func (t *Texture) Free() error {
+	t.engine.textureMu.Lock()
+	defer t.engine.textureMu.Unlock()
+
+	for name, tex := range t.engine.textures {
+		if tex == t {
+			delete(t.engine.textures, name)
+			break
+		}
+	}
+	return nil
+}
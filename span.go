@@ -0,0 +1,166 @@
+package render
+
+import (
+	"fmt"
+	"math"
+	"sort"
+)
+
+// Span is a horizontal run of pixels on row Y, from X1 to X2 inclusive.
+//
+// The scanline shape functions produce spans, which the engines fill in
+// with a single call each instead of drawing every pixel.
+type Span struct {
+	Y, X1, X2 int
+}
+
+func (s Span) String() string {
+	return fmt.Sprintf("Span<%d: %d..%d>", s.Y, s.X1, s.X2)
+}
+
+// Rect returns the pixels of the span as a one pixel tall Rect.
+func (s Span) Rect() Rect {
+	return Rect{X: s.X1, Y: s.Y, W: s.X2 - s.X1 + 1, H: 1}
+}
+
+// mergeSpans sorts the spans by row and joins the ones that overlap or
+// touch, so each pixel is covered only once.
+func mergeSpans(spans []Span) []Span {
+	if len(spans) < 2 {
+		return spans
+	}
+
+	sort.Slice(spans, func(i, j int) bool {
+		if spans[i].Y != spans[j].Y {
+			return spans[i].Y < spans[j].Y
+		}
+		return spans[i].X1 < spans[j].X1
+	})
+
+	var merged = spans[:1]
+	for _, span := range spans[1:] {
+		last := &merged[len(merged)-1]
+		if span.Y == last.Y && span.X1 <= last.X2+1 {
+			if span.X2 > last.X2 {
+				last.X2 = span.X2
+			}
+			continue
+		}
+		merged = append(merged, span)
+	}
+	return merged
+}
+
+// intersectSpans returns the pixels of the first row of spans that are also
+// in the second. Both must be sorted and not overlap, like the spans of one
+// row from mergeSpans; the result is on the row of the first.
+func intersectSpans(a, b []Span) []Span {
+	var result []Span
+	for i, j := 0, 0; i < len(a) && j < len(b); {
+		var (
+			x1 = maxInt(a[i].X1, b[j].X1)
+			x2 = minInt(a[i].X2, b[j].X2)
+		)
+		if x1 <= x2 {
+			result = append(result, Span{Y: a[i].Y, X1: x1, X2: x2})
+		}
+		if a[i].X2 < b[j].X2 {
+			i++
+		} else {
+			j++
+		}
+	}
+	return result
+}
+
+// subtractSpans returns the parts of a span that are not covered by a
+// sorted list of spans inside it.
+func subtractSpans(span Span, holes []Span) []Span {
+	var (
+		result []Span
+		x      = span.X1
+	)
+	for _, hole := range holes {
+		if hole.X1 > x {
+			result = append(result, Span{Y: span.Y, X1: x, X2: hole.X1 - 1})
+		}
+		x = hole.X2 + 1
+	}
+	if x <= span.X2 {
+		result = append(result, Span{Y: span.Y, X1: x, X2: span.X2})
+	}
+	return result
+}
+
+// fillConvex scans a convex polygon and calls fn with each horizontal run of
+// pixels whose centers are inside it, from x1 to x2 inclusive.
+func fillConvex(poly [][2]float64, fn func(y, x1, x2 int)) {
+	if len(poly) < 3 {
+		return
+	}
+
+	var minY, maxY = poly[0][1], poly[0][1]
+	for _, pt := range poly[1:] {
+		minY = math.Min(minY, pt[1])
+		maxY = math.Max(maxY, pt[1])
+	}
+
+	for y := int(math.Floor(minY)); float64(y) < maxY; y++ {
+		var (
+			cy     = float64(y) + 0.5
+			left   = math.Inf(1)
+			right  = math.Inf(-1)
+			inside bool
+		)
+
+		// Find where the scanline crosses the edges.
+		for i := range poly {
+			var (
+				a = poly[i]
+				b = poly[(i+1)%len(poly)]
+			)
+			if (a[1] <= cy && cy < b[1]) || (b[1] <= cy && cy < a[1]) {
+				x := a[0] + (cy-a[1])*(b[0]-a[0])/(b[1]-a[1])
+				left = math.Min(left, x)
+				right = math.Max(right, x)
+				inside = true
+			}
+		}
+		if !inside {
+			continue
+		}
+
+		// Pixels whose centers are between the crossings.
+		var (
+			x1 = int(math.Ceil(left - 0.5))
+			x2 = int(math.Ceil(right-0.5)) - 1
+		)
+		if x2 >= x1 {
+			fn(y, x1, x2)
+		}
+	}
+}
+
+// fillDisc scans a disc and calls fn with each horizontal run of pixels
+// whose centers are inside it, from x1 to x2 inclusive.
+func fillDisc(cx, cy, radius float64, fn func(y, x1, x2 int)) {
+	if radius <= 0 {
+		return
+	}
+
+	for y := int(math.Floor(cy - radius)); float64(y) < cy+radius; y++ {
+		var dy = float64(y) + 0.5 - cy
+		if math.Abs(dy) >= radius {
+			continue
+		}
+
+		var (
+			dx = math.Sqrt(radius*radius - dy*dy)
+			x1 = int(math.Ceil(cx - dx - 0.5))
+			x2 = int(math.Ceil(cx+dx-0.5)) - 1
+		)
+		if x2 >= x1 {
+			fn(y, x1, x2)
+		}
+	}
+}
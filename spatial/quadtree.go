@@ -0,0 +1,296 @@
+// Package spatial provides a quadtree index of rects, for quickly finding
+// the objects of a large scene that are in view or under the mouse cursor
+// without checking every one of them.
+//
+//	tree := spatial.NewQuadtree(levelBounds)
+//	for _, actor := range actors {
+//		tree.Insert(actor, actor.Rect())
+//	}
+//
+//	// Each tick, after an actor moves:
+//	tree.Move(actor, actor.Rect())
+//
+//	// Cull to the viewport and hit-test the cursor.
+//	visible := tree.Query(camera.View())
+//	hovered := tree.QueryPoint(render.NewPoint(ev.CursorX, ev.CursorY))
+package spatial
+
+import "git.kirsle.net/go/render"
+
+// Defaults for NewQuadtree.
+const (
+	// DefaultMaxItems is how many items a node holds before it splits into
+	// four smaller ones.
+	DefaultMaxItems = 8
+
+	// DefaultMaxDepth is how many times the nodes may split.
+	DefaultMaxDepth = 8
+)
+
+// Quadtree indexes items by their rects. Each node of the tree covers a part
+// of the bounds and splits into four quarters when it gets full, so a query
+// only looks at the nodes that overlap it.
+//
+// Items can be any comparable value, such as pointers to actors or their
+// IDs. Like Rect.Intersection, a rect covers the pixels from X to X+W-1 and
+// Y to Y+H-1; items with empty rects are never found by a query.
+//
+// Items outside of the tree's bounds can still be added, and are kept in a
+// list that every query checks. Choose bounds that hold most of the scene,
+// such as the size of the level.
+type Quadtree struct {
+	MaxItems int
+	MaxDepth int
+
+	root    *node
+	outside []*entry // items not inside the bounds
+	items   map[interface{}]*entry
+}
+
+// node is a square of the tree, holding the items that fit inside of it but
+// not inside one of its children.
+type node struct {
+	bounds   render.Rect
+	depth    int
+	items    []*entry
+	children *[4]node
+}
+
+// entry is an item in the tree and the node holding it.
+type entry struct {
+	item interface{}
+	rect render.Rect
+	node *node // nil when outside
+}
+
+// NewQuadtree creates an empty quadtree covering an area.
+func NewQuadtree(bounds render.Rect) *Quadtree {
+	return &Quadtree{
+		MaxItems: DefaultMaxItems,
+		MaxDepth: DefaultMaxDepth,
+		root:     &node{bounds: bounds.Normalize()},
+		items:    map[interface{}]*entry{},
+	}
+}
+
+// Len returns the number of items in the tree.
+func (q *Quadtree) Len() int {
+	return len(q.items)
+}
+
+// Rect returns the rect an item was added with, and whether it's in the
+// tree.
+func (q *Quadtree) Rect(item interface{}) (render.Rect, bool) {
+	if e, ok := q.items[item]; ok {
+		return e.rect, true
+	}
+	return render.Rect{}, false
+}
+
+// Insert adds an item with its rect. Inserting an item that is already in
+// the tree moves it instead.
+func (q *Quadtree) Insert(item interface{}, rect render.Rect) {
+	if _, ok := q.items[item]; ok {
+		q.Move(item, rect)
+		return
+	}
+
+	var e = &entry{
+		item: item,
+		rect: rect.Normalize(),
+	}
+	q.items[item] = e
+	q.place(e)
+}
+
+// Remove an item from the tree, returning whether it was there.
+func (q *Quadtree) Remove(item interface{}) bool {
+	e, ok := q.items[item]
+	if !ok {
+		return false
+	}
+	delete(q.items, item)
+	q.unlink(e)
+	return true
+}
+
+// Move changes the rect of an item, adding it to the tree if it wasn't
+// already.
+func (q *Quadtree) Move(item interface{}, rect render.Rect) {
+	e, ok := q.items[item]
+	if !ok {
+		q.Insert(item, rect)
+		return
+	}
+
+	// Small moves usually stay in the same node.
+	rect = rect.Normalize()
+	if n := e.node; n != nil && contains(n.bounds, rect) &&
+		(n.children == nil || n.child(rect) == nil) {
+		e.rect = rect
+		return
+	}
+
+	q.unlink(e)
+	e.rect = rect
+	q.place(e)
+}
+
+// Query returns the items whose rects overlap an area, in no particular
+// order.
+func (q *Quadtree) Query(rect render.Rect) []interface{} {
+	var result []interface{}
+	q.Search(rect, func(item interface{}, _ render.Rect) bool {
+		result = append(result, item)
+		return true
+	})
+	return result
+}
+
+// QueryPoint returns the items whose rects contain a pixel, such as the one
+// under the mouse cursor, in no particular order.
+func (q *Quadtree) QueryPoint(p render.Point) []interface{} {
+	return q.Query(render.Rect{X: p.X, Y: p.Y, W: 1, H: 1})
+}
+
+// Search calls fn with each item whose rect overlaps an area, and its rect,
+// until fn returns false. It doesn't allocate a slice for the results.
+func (q *Quadtree) Search(rect render.Rect, fn func(item interface{}, rect render.Rect) bool) {
+	rect = rect.Normalize()
+	if rect.IsEmpty() {
+		return
+	}
+
+	for _, e := range q.outside {
+		if overlaps(e.rect, rect) && !fn(e.item, e.rect) {
+			return
+		}
+	}
+	q.root.search(rect, fn)
+}
+
+// search the node and its children, returning false if fn stopped it.
+func (n *node) search(rect render.Rect, fn func(item interface{}, rect render.Rect) bool) bool {
+	if !overlaps(n.bounds, rect) {
+		return true
+	}
+
+	for _, e := range n.items {
+		if overlaps(e.rect, rect) && !fn(e.item, e.rect) {
+			return false
+		}
+	}
+	if n.children != nil {
+		for i := range n.children {
+			if !n.children[i].search(rect, fn) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// place an entry in the deepest node that holds its rect.
+func (q *Quadtree) place(e *entry) {
+	if !contains(q.root.bounds, e.rect) {
+		e.node = nil
+		q.outside = append(q.outside, e)
+		return
+	}
+
+	var n = q.root
+	for n.children != nil {
+		var child = n.child(e.rect)
+		if child == nil {
+			break
+		}
+		n = child
+	}
+
+	e.node = n
+	n.items = append(n.items, e)
+	if n.children == nil && len(n.items) > q.MaxItems && n.depth < q.MaxDepth {
+		n.split()
+	}
+}
+
+// unlink an entry from the node or list it's in.
+func (q *Quadtree) unlink(e *entry) {
+	if e.node == nil {
+		q.outside = removeEntry(q.outside, e)
+		return
+	}
+	e.node.items = removeEntry(e.node.items, e)
+	e.node = nil
+}
+
+// split a node into quarters, moving down the items that fit inside one.
+func (n *node) split() {
+	var (
+		b     = n.bounds
+		halfW = b.W / 2
+		halfH = b.H / 2
+	)
+	if halfW == 0 || halfH == 0 {
+		return
+	}
+
+	n.children = &[4]node{
+		{bounds: render.Rect{X: b.X, Y: b.Y, W: halfW, H: halfH}},
+		{bounds: render.Rect{X: b.X + halfW, Y: b.Y, W: b.W - halfW, H: halfH}},
+		{bounds: render.Rect{X: b.X, Y: b.Y + halfH, W: halfW, H: b.H - halfH}},
+		{bounds: render.Rect{X: b.X + halfW, Y: b.Y + halfH, W: b.W - halfW, H: b.H - halfH}},
+	}
+	for i := range n.children {
+		n.children[i].depth = n.depth + 1
+	}
+
+	var items = n.items
+	n.items = nil
+	for _, e := range items {
+		if child := n.child(e.rect); child != nil {
+			e.node = child
+			child.items = append(child.items, e)
+		} else {
+			n.items = append(n.items, e)
+		}
+	}
+}
+
+// child returns the child node that holds a rect, or nil if it straddles
+// more than one of them.
+func (n *node) child(rect render.Rect) *node {
+	for i := range n.children {
+		if contains(n.children[i].bounds, rect) {
+			return &n.children[i]
+		}
+	}
+	return nil
+}
+
+// contains returns whether the rect b lies inside a, including empty rects
+// at its edges.
+func contains(a, b render.Rect) bool {
+	return b.X >= a.X && b.X+b.W <= a.X+a.W &&
+		b.Y >= a.Y && b.Y+b.H <= a.Y+a.H
+}
+
+// overlaps returns whether two normalized rects share a pixel.
+func overlaps(a, b render.Rect) bool {
+	return !a.IsEmpty() && !b.IsEmpty() &&
+		a.X < b.X+b.W && b.X < a.X+a.W &&
+		a.Y < b.Y+b.H && b.Y < a.Y+a.H
+}
+
+// removeEntry removes an entry from a list without keeping the order.
+func removeEntry(list []*entry, e *entry) []*entry {
+	for i, other := range list {
+		if other == e {
+			last := len(list) - 1
+			list[i] = list[last]
+			list[last] = nil
+			return list[:last]
+		}
+	}
+	return list
+}
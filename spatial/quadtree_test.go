@@ -0,0 +1,146 @@
+package spatial_test
+
+import (
+	"math/rand"
+	"sort"
+	"testing"
+
+	"git.kirsle.net/go/render"
+	"git.kirsle.net/go/render/spatial"
+)
+
+// sorted returns the int items of a query in order, for comparing.
+func sorted(items []interface{}) []int {
+	var result = make([]int, len(items))
+	for i, item := range items {
+		result[i] = item.(int)
+	}
+	sort.Ints(result)
+	return result
+}
+
+func equal(a, b []int) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func TestQuadtree(t *testing.T) {
+	var (
+		tree  = spatial.NewQuadtree(render.Rect{X: 0, Y: 0, W: 1000, H: 1000})
+		rects = map[int]render.Rect{}
+		rng   = rand.New(rand.NewSource(1))
+	)
+	randomRect := func() render.Rect {
+		// Mostly inside the bounds, some straddling or beyond them.
+		return render.Rect{
+			X: rng.Intn(1200) - 100,
+			Y: rng.Intn(1200) - 100,
+			W: rng.Intn(60) + 1,
+			H: rng.Intn(60) + 1,
+		}
+	}
+
+	// Check the tree against a linear scan of every rect.
+	check := func(step string) {
+		for i := 0; i < 20; i++ {
+			var (
+				query  = randomRect()
+				expect []int
+			)
+			query.W *= 4
+			for item, rect := range rects {
+				if !rect.Intersection(query).IsEmpty() {
+					expect = append(expect, item)
+				}
+			}
+			sort.Ints(expect)
+			if actual := sorted(tree.Query(query)); !equal(actual, expect) {
+				t.Fatalf("%s: Query(%s): expected %v, got %v", step, query, expect, actual)
+			}
+		}
+		if tree.Len() != len(rects) {
+			t.Fatalf("%s: expected %d items, got %d", step, len(rects), tree.Len())
+		}
+	}
+
+	for i := 0; i < 2000; i++ {
+		rects[i] = randomRect()
+		tree.Insert(i, rects[i])
+	}
+	check("insert")
+
+	for i := 0; i < 2000; i += 2 {
+		// Small steps like a walking actor, and a few teleports.
+		var rect = rects[i]
+		if i%10 == 0 {
+			rect = randomRect()
+		} else {
+			rect.X += rng.Intn(9) - 4
+			rect.Y += rng.Intn(9) - 4
+		}
+		rects[i] = rect
+		tree.Move(i, rect)
+	}
+	check("move")
+
+	for i := 0; i < 2000; i += 3 {
+		if !tree.Remove(i) {
+			t.Errorf("Remove(%d): expected it to be in the tree", i)
+		}
+		delete(rects, i)
+	}
+	if tree.Remove(0) {
+		t.Errorf("Remove(0): expected it to be gone already")
+	}
+	check("remove")
+}
+
+func TestQuadtreePoint(t *testing.T) {
+	var tree = spatial.NewQuadtree(render.Rect{X: 0, Y: 0, W: 100, H: 100})
+	tree.Insert("box", render.Rect{X: 10, Y: 10, W: 10, H: 10})
+	tree.Insert("far away", render.Rect{X: 500, Y: 500, W: 1, H: 1})
+	tree.Insert("empty", render.Rect{X: 12, Y: 12})
+
+	var tests = []struct {
+		Point  render.Point
+		Expect int
+	}{
+		{render.NewPoint(10, 10), 1},
+		{render.NewPoint(19, 19), 1},
+		{render.NewPoint(20, 15), 0}, // X+W is past the right edge
+		{render.NewPoint(12, 12), 1},
+		{render.NewPoint(500, 500), 1},
+	}
+	for _, test := range tests {
+		if actual := tree.QueryPoint(test.Point); len(actual) != test.Expect {
+			t.Errorf("QueryPoint(%s): expected %d items, got %v", test.Point, test.Expect, actual)
+		}
+	}
+
+	if rect, ok := tree.Rect("far away"); !ok || rect.X != 500 {
+		t.Errorf("Rect: expected the far away rect, got %s (%v)", rect, ok)
+	}
+
+	// Moving an item outside of the bounds back in.
+	tree.Move("far away", render.Rect{X: 50, Y: 50, W: 5, H: 5})
+	if actual := tree.Query(render.Rect{X: 0, Y: 0, W: 100, H: 100}); len(actual) != 2 {
+		t.Errorf("Query: expected both items, got %v", actual)
+	}
+
+	// Searching stops early.
+	var n int
+	tree.Search(render.Rect{X: 0, Y: 0, W: 100, H: 100}, func(item interface{}, rect render.Rect) bool {
+		n++
+		return false
+	})
+	if n != 1 {
+		t.Errorf("Search: expected to stop after 1 item, got %d", n)
+	}
+}
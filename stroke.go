@@ -0,0 +1,267 @@
+package render
+
+import "math"
+
+// LineCap is the shape drawn at the open ends of a stroke.
+type LineCap int
+
+// LineCap values.
+const (
+	CapButt   LineCap = iota // flat, ending exactly at the endpoint
+	CapRound                 // a half circle around the endpoint
+	CapSquare                // flat, extended past the endpoint by half the width
+)
+
+// LineJoin is the shape drawn where two segments of a stroke meet.
+type LineJoin int
+
+// LineJoin values.
+const (
+	JoinMiter LineJoin = iota // sharp corners, beveled past the MiterLimit
+	JoinRound                 // rounded corners
+	JoinBevel                 // corners cut off flat
+)
+
+// DefaultMiterLimit is used when a StrokeStyle has no MiterLimit, and matches
+// the HTML Canvas default.
+const DefaultMiterLimit = 10
+
+// StrokeStyle configures how thick lines are drawn by Engine.DrawPolyline.
+//
+// The zero value draws one pixel wide lines with butt caps and miter joins.
+type StrokeStyle struct {
+	Width int      `json:"width,omitempty"`
+	Cap   LineCap  `json:"cap,omitempty"`
+	Join  LineJoin `json:"join,omitempty"`
+
+	// MiterLimit is how far a miter join may stick out, as a multiple of
+	// half the width, before it's drawn beveled instead.
+	MiterLimit float64 `json:"miterLimit,omitempty"`
+}
+
+// Spans returns the rows of pixels covered by a polyline drawn with the
+// stroke style. Each pixel is covered by exactly one span, so translucent
+// colors don't build up where segments overlap.
+//
+// The line runs through the centers of the pixels at each point. If the last
+// point is the same as the first, the polyline is closed: its ends are
+// joined instead of capped. One pixel wide lines cover the pixels at both
+// ends, like Engine.DrawLine.
+func (s StrokeStyle) Spans(points []Point) []Span {
+	var centers = make([][2]float64, len(points))
+	for i, pt := range points {
+		centers[i] = [2]float64{float64(pt.X) + 0.5, float64(pt.Y) + 0.5}
+	}
+	return mergeSpans(s.spans(nil, centers))
+}
+
+// spans appends the rows of pixels covered by a polyline through fractional
+// coordinates, where pixel centers are at +0.5. The result is not merged.
+func (s StrokeStyle) spans(spans []Span, points [][2]float64) []Span {
+	if len(points) == 0 {
+		return spans
+	}
+
+	var half = float64(s.width()) / 2
+	add := func(y, x1, x2 int) {
+		spans = append(spans, Span{Y: y, X1: x1, X2: x2})
+	}
+
+	// Line segments between the points, skipping repeated ones.
+	var centers = make([][2]float64, 0, len(points))
+	for i, pt := range points {
+		if i > 0 && pt == points[i-1] {
+			continue
+		}
+		centers = append(centers, pt)
+	}
+
+	var closed = len(centers) > 2 && centers[0] == centers[len(centers)-1]
+	if closed {
+		centers = centers[:len(centers)-1]
+	}
+
+	// One pixel wide butt caps would leave out a pixel at one end, where the
+	// line ends on the edge between two pixels, so they're drawn square to
+	// cover both ends like DrawLine.
+	var lineCap = s.Cap
+	if lineCap == CapButt && s.width() == 1 {
+		lineCap = CapSquare
+	}
+
+	// A single point is drawn as its caps.
+	if len(centers) == 1 {
+		var c = centers[0]
+		switch lineCap {
+		case CapRound:
+			fillDisc(c[0], c[1], half, add)
+		case CapSquare:
+			fillConvex([][2]float64{
+				{c[0] - half, c[1] - half},
+				{c[0] + half, c[1] - half},
+				{c[0] + half, c[1] + half},
+				{c[0] - half, c[1] + half},
+			}, add)
+		}
+		return spans
+	}
+
+	var segments = len(centers) - 1
+	if closed {
+		segments = len(centers)
+	}
+
+	for i := 0; i < segments; i++ {
+		var (
+			a    = centers[i]
+			b    = centers[(i+1)%len(centers)]
+			d    = direction(a, b)
+			n    = [2]float64{-d[1] * half, d[0] * half}
+			head = [2]float64{}
+			tail = [2]float64{}
+		)
+
+		// Square caps extend the open ends of the line.
+		if !closed && lineCap == CapSquare {
+			if i == 0 {
+				head = [2]float64{-d[0] * half, -d[1] * half}
+			}
+			if i == segments-1 {
+				tail = [2]float64{d[0] * half, d[1] * half}
+			}
+		}
+
+		fillConvex([][2]float64{
+			{a[0] + head[0] + n[0], a[1] + head[1] + n[1]},
+			{b[0] + tail[0] + n[0], b[1] + tail[1] + n[1]},
+			{b[0] + tail[0] - n[0], b[1] + tail[1] - n[1]},
+			{a[0] + head[0] - n[0], a[1] + head[1] - n[1]},
+		}, add)
+	}
+
+	// Round caps.
+	if !closed && lineCap == CapRound {
+		var (
+			first = centers[0]
+			last  = centers[len(centers)-1]
+		)
+		fillDisc(first[0], first[1], half, add)
+		fillDisc(last[0], last[1], half, add)
+	}
+
+	// Joins where the segments meet.
+	for i := range centers {
+		if !closed && (i == 0 || i == len(centers)-1) {
+			continue
+		}
+
+		var (
+			prev = centers[(i+len(centers)-1)%len(centers)]
+			v    = centers[i]
+			next = centers[(i+1)%len(centers)]
+		)
+		s.join(prev, v, next, half, add)
+	}
+
+	return spans
+}
+
+// join fills the corner where the segment from prev to v meets the segment
+// from v to next.
+func (s StrokeStyle) join(prev, v, next [2]float64, half float64, add func(y, x1, x2 int)) {
+	var (
+		d1    = direction(prev, v)
+		d2    = direction(v, next)
+		cross = d1[0]*d2[1] - d1[1]*d2[0]
+		dot   = d1[0]*d2[0] + d1[1]*d2[1]
+	)
+
+	// Straight on: the segments already meet.
+	if math.Abs(cross) < 1e-9 && dot > 0 {
+		return
+	}
+
+	if s.Join == JoinRound {
+		fillDisc(v[0], v[1], half, add)
+		return
+	}
+
+	// The corner sticks out on the side the line turns away from.
+	var side = 1.0
+	if cross > 0 {
+		side = -1
+	}
+	var (
+		n1 = [2]float64{-d1[1] * half * side, d1[0] * half * side}
+		n2 = [2]float64{-d2[1] * half * side, d2[0] * half * side}
+		p1 = [2]float64{v[0] + n1[0], v[1] + n1[1]}
+		p2 = [2]float64{v[0] + n2[0], v[1] + n2[1]}
+	)
+
+	if s.Join == JoinMiter {
+		var limit = s.MiterLimit
+		if limit <= 0 {
+			limit = DefaultMiterLimit
+		}
+
+		// The miter's tip is along the bisector of the two offsets, at a
+		// distance of half / cos(theta/2).
+		var (
+			mx, my = n1[0] + n2[0], n1[1] + n2[1]
+			length = math.Hypot(mx, my)
+		)
+		if length > 1e-9 {
+			var cos = (mx*n1[0] + my*n1[1]) / (length * half)
+			if cos > 0 && 1/cos <= limit {
+				var scale = half / cos / length
+				fillConvex([][2]float64{
+					v,
+					p1,
+					{v[0] + mx*scale, v[1] + my*scale},
+					p2,
+				}, add)
+				return
+			}
+		}
+	}
+
+	// Bevel.
+	fillConvex([][2]float64{v, p1, p2}, add)
+}
+
+// width returns the stroke width, at least one pixel.
+func (s StrokeStyle) width() int {
+	if s.Width < 1 {
+		return 1
+	}
+	return s.Width
+}
+
+// direction returns the unit vector from a to b.
+func direction(a, b [2]float64) [2]float64 {
+	var (
+		dx     = b[0] - a[0]
+		dy     = b[1] - a[1]
+		length = math.Hypot(dx, dy)
+	)
+	if length == 0 {
+		return [2]float64{}
+	}
+	return [2]float64{dx / length, dy / length}
+}
+
+// Outline returns the corners of the rect as a closed polyline, covering the
+// same pixels as Engine.DrawRect when stroked one pixel wide.
+func (r Rect) Outline() []Point {
+	var (
+		x2 = r.X + r.W - 1
+		y2 = r.Y + r.H - 1
+	)
+	return []Point{
+		{X: r.X, Y: r.Y},
+		{X: x2, Y: r.Y},
+		{X: x2, Y: y2},
+		{X: r.X, Y: y2},
+		{X: r.X, Y: r.Y},
+	}
+}
@@ -0,0 +1,118 @@
+package render_test
+
+import (
+	"testing"
+
+	"git.kirsle.net/go/render"
+)
+
+// spanPixels collects the pixels covered by spans, failing if any pixel is
+// covered twice.
+func spanPixels(t *testing.T, spans []render.Span) map[render.Point]bool {
+	var pixels = map[render.Point]bool{}
+	for _, span := range spans {
+		for x := span.X1; x <= span.X2; x++ {
+			pt := render.NewPoint(x, span.Y)
+			if pixels[pt] {
+				t.Errorf("pixel %s is covered by more than one span", pt)
+			}
+			pixels[pt] = true
+		}
+	}
+	return pixels
+}
+
+func TestStrokeSpans(t *testing.T) {
+	// A thin outline of a rect covers the same pixels as DrawRect.
+	var (
+		rect   = render.Rect{X: 2, Y: 3, W: 10, H: 6}
+		pixels = spanPixels(t, render.StrokeStyle{}.Spans(rect.Outline()))
+	)
+	render.RectPoints(render.NewPoint(2, 3), render.NewPoint(11, 8))(func(pt render.Point) bool {
+		if !pixels[pt] {
+			t.Errorf("thin outline: expected %s to be covered", pt)
+		}
+		delete(pixels, pt)
+		return true
+	})
+	if len(pixels) > 0 {
+		t.Errorf("thin outline: unexpected pixels covered: %v", pixels)
+	}
+
+	// A horizontal line 4 pixels wide, with each type of cap.
+	var line = []render.Point{render.NewPoint(10, 10), render.NewPoint(20, 10)}
+	var tests = []struct {
+		Cap      render.LineCap
+		Expect   int // number of pixels covered
+		Inside   render.Point
+		Outside  render.Point
+		CapPixel render.Point
+	}{
+		{render.CapButt, 10 * 4, render.NewPoint(15, 8), render.NewPoint(15, 12), render.NewPoint(9, 10)},
+		{render.CapSquare, 14 * 4, render.NewPoint(15, 11), render.NewPoint(15, 7), render.NewPoint(8, 9)},
+	}
+	for _, test := range tests {
+		pixels := spanPixels(t, render.StrokeStyle{Width: 4, Cap: test.Cap}.Spans(line))
+		if len(pixels) != test.Expect {
+			t.Errorf("cap %d: expected %d pixels, got %d", test.Cap, test.Expect, len(pixels))
+		}
+		if !pixels[test.Inside] || pixels[test.Outside] {
+			t.Errorf("cap %d: expected %s covered and %s not", test.Cap, test.Inside, test.Outside)
+		}
+		if covered := pixels[test.CapPixel]; covered != (test.Cap == render.CapSquare) {
+			t.Errorf("cap %d: pixel %s covered=%t", test.Cap, test.CapPixel, covered)
+		}
+	}
+
+	// Joins: a right angle turn. The miter fills the outer corner, the
+	// bevel cuts it off and round is in between.
+	var (
+		corner = []render.Point{render.NewPoint(0, 10), render.NewPoint(10, 10), render.NewPoint(10, 20)}
+		counts = map[render.LineJoin]int{}
+	)
+	for _, join := range []render.LineJoin{render.JoinMiter, render.JoinRound, render.JoinBevel} {
+		pixels := spanPixels(t, render.StrokeStyle{Width: 6, Join: join}.Spans(corner))
+		counts[join] = len(pixels)
+		if join == render.JoinMiter && !pixels[render.NewPoint(12, 8)] {
+			t.Errorf("miter join: expected the outer corner to be filled")
+		}
+		if join == render.JoinBevel && pixels[render.NewPoint(12, 7)] {
+			t.Errorf("bevel join: expected the outer corner to be cut off")
+		}
+	}
+	if !(counts[render.JoinBevel] < counts[render.JoinRound] && counts[render.JoinRound] < counts[render.JoinMiter]) {
+		t.Errorf("joins: expected bevel < round < miter in size, got %v", counts)
+	}
+
+	// A single point with round caps is a dot.
+	if pixels := spanPixels(t, render.StrokeStyle{Width: 5, Cap: render.CapRound}.Spans(line[:1])); len(pixels) < 15 {
+		t.Errorf("round dot: expected a disc, got %d pixels", len(pixels))
+	}
+}
+
+func TestStrokeSpansEnds(t *testing.T) {
+	// A thin open line covers both of its ends, like DrawLine, whichever way
+	// it goes.
+	var p = render.NewPoint
+	var tests = [][2]render.Point{
+		{p(0, 0), p(5, 0)},
+		{p(5, 0), p(0, 0)},
+		{p(0, 0), p(0, 5)},
+		{p(0, 5), p(0, 0)},
+		{p(0, 0), p(5, 5)},
+		{p(0, 0), p(5, 2)},
+		{p(3, 3), p(3, 3)},
+	}
+	for _, test := range tests {
+		var (
+			pixels = spanPixels(t, render.StrokeStyle{}.Spans(test[:]))
+			expect = len(collect(render.LinePoints(test[0], test[1])))
+		)
+		if !pixels[test[0]] || !pixels[test[1]] {
+			t.Errorf("%s to %s: expected both ends to be covered", test[0], test[1])
+		}
+		if len(pixels) != expect {
+			t.Errorf("%s to %s: expected %d pixels like LinePoints, got %d", test[0], test[1], expect, len(pixels))
+		}
+	}
+}